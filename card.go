@@ -0,0 +1,43 @@
+package diceware
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// cardRule is the blank line FormatCard leaves for a handwritten hint.
+var cardRule = strings.Repeat("_", 40)
+
+// FormatCard writes p to w as a plain text card to print and keep as a
+// recovery sheet: the passphrase, its words numbered one per line, the
+// entropy and the wordlist's language, and blank lines to write a hint
+// on. Unlike PrintWordlist, which prints a whole wordlist for rolling
+// dice, the card holds one secret, so treat the printout like the
+// passphrase itself. It returns the first error writing to w.
+func FormatCard(p Passphrase, w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "DICEWARE PASSPHRASE")
+	fmt.Fprintln(bw, "===================")
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "Passphrase: %s\n", p.text)
+	fmt.Fprintf(bw, "Entropy:    %.1f bits", p.entropy)
+	for _, info := range ListLanguages() {
+		if info.Language == p.lang {
+			fmt.Fprintf(bw, " (%s wordlist)", info.Name)
+			break
+		}
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw)
+	fmt.Fprintln(bw, "Words:")
+	for i, word := range p.words {
+		fmt.Fprintf(bw, "  %2d. %s\n", i+1, word)
+	}
+	fmt.Fprintln(bw)
+	fmt.Fprintf(bw, "Hint: %s\n\n", cardRule)
+	fmt.Fprintf(bw, "      %s\n\n", cardRule)
+	fmt.Fprintln(bw, "Keep this card somewhere safe and private.")
+	return bw.Flush()
+}
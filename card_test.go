@@ -0,0 +1,48 @@
+package diceware
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatCard(t *testing.T) {
+	p, err := GeneratePassphrase(6, WithSeparator("-"))
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error = %v", err)
+	}
+	var buf bytes.Buffer
+	if err := FormatCard(p, &buf); err != nil {
+		t.Fatalf("FormatCard() error = %v", err)
+	}
+	card := buf.String()
+
+	want := []string{
+		"Passphrase: " + p.String() + "\n",
+		fmt.Sprintf("Entropy:    %.1f bits (English wordlist)\n", p.Entropy()),
+		"Hint: ____",
+	}
+	for i, word := range p.Words() {
+		want = append(want, fmt.Sprintf("  %2d. %s\n", i+1, word))
+	}
+	for _, s := range want {
+		if !strings.Contains(card, s) {
+			t.Errorf("FormatCard() card is missing %q:\n%s", s, card)
+		}
+	}
+
+	// Custom wordlists have no language to name
+	custom, _ := NewWordlist("custom", map[string]string{"11111": "otter", "11112": "panda"})
+	p, err = GeneratePassphrase(3, WithWordlists(custom))
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error = %v", err)
+	}
+	buf.Reset()
+	if err := FormatCard(p, &buf); err != nil {
+		t.Fatalf("FormatCard() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "wordlist)") {
+		t.Errorf("FormatCard() names a language for a custom wordlist:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,145 @@
+package diceware
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CapitalizationMode selects how the words of a passphrase are cased.
+type CapitalizationMode int
+
+const (
+	// CapFirst capitalizes the first letter of every word, e.g.
+	// "ColtDefaultArousal". This is the default.
+	CapFirst CapitalizationMode = iota
+	// CapNone leaves every word lowercase, e.g. "coltdefaultarousal", for
+	// password fields that reject upper case letters.
+	CapNone
+	// CapUpper upper-cases every word, e.g. "COLTDEFAULTAROUSAL".
+	CapUpper
+	// CapRandom capitalizes the first letter of each word or leaves it
+	// lowercase at random, e.g. "ColtdefaultArousal", adding up to a bit of
+	// entropy per word (see EntropyBreakdown.Casing).
+	CapRandom
+	// CapSentence capitalizes only the first word and leaves the others
+	// lowercase, e.g. "Coltdefaultarousal", like the start of a sentence.
+	CapSentence
+	// CapAdaptive picks the casing by the separator: CapSentence when the
+	// words are separated, e.g. "Colt default arousal", where capitals
+	// would be redundant, and CapFirst when they run together, e.g.
+	// "ColtDefaultArousal", where they mark where each word starts.
+	CapAdaptive
+)
+
+// capModes names the modes for String and ParseCapitalizationMode.
+var capModes = []struct {
+	mode CapitalizationMode
+	name string
+}{
+	{CapFirst, "first"},
+	{CapNone, "none"},
+	{CapUpper, "upper"},
+	{CapRandom, "random"},
+	{CapSentence, "sentence"},
+	{CapAdaptive, "adaptive"},
+}
+
+// String returns the mode's name as accepted by the CLI's --case flag.
+func (m CapitalizationMode) String() string {
+	for _, c := range capModes {
+		if c.mode == m {
+			return c.name
+		}
+	}
+	return fmt.Sprintf("CapitalizationMode(%d)", int(m))
+}
+
+// ParseCapitalizationMode returns the mode with the given name ("first",
+// "none", "upper", "random", "sentence" or "adaptive"), ignoring case.
+// "lower" is accepted for CapNone.
+func ParseCapitalizationMode(name string) (CapitalizationMode, error) {
+	if strings.EqualFold(name, "lower") {
+		return CapNone, nil
+	}
+	for _, c := range capModes {
+		if strings.EqualFold(c.name, name) {
+			return c.mode, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown capitalization mode %q (use first, none, upper, random, sentence or adaptive)", name)
+}
+
+// apply cases word according to the mode. CapRandom needs a coin flip per
+// word and is handled by options.applyCase instead, and CapSentence and
+// CapAdaptive depend on the word's position and the separators and are
+// resolved by options.caseMode.
+func (m CapitalizationMode) apply(word string) string {
+	switch m {
+	case CapNone:
+		return strings.ToLower(word)
+	case CapUpper:
+		return strings.ToUpper(word)
+	}
+	return capitalize(word)
+}
+
+// valid reports whether m is one of the defined modes.
+func (m CapitalizationMode) valid() bool {
+	return m >= CapFirst && m <= CapAdaptive
+}
+
+// WithCapitalization sets how words are cased; the default is CapFirst.
+// Apart from CapRandom, casing is the same for every passphrase, so it
+// doesn't change the entropy. It overrides WithCapitalizer and
+// WithCasePattern.
+func WithCapitalization(mode CapitalizationMode) Option {
+	return func(o *options) {
+		o.capMode = mode
+		o.capitalize = nil
+		o.casePattern = nil
+	}
+}
+
+// WithCasePattern cases the words with the modes of pattern in turn, e.g.
+// []CapitalizationMode{CapFirst, CapNone} for "ColtdefaultArousalthimble"
+// or {CapNone, CapUpper} for every other word upper case. A pattern
+// shorter than the passphrase starts over, and a WithNumberWord number
+// isn't counted. Only the CapRandom entries add entropy, each as much as
+// WithCapitalization(CapRandom) does for one word.
+//
+// An empty pattern falls back to WithCapitalization. It overrides
+// WithCapitalization and WithCapitalizer.
+func WithCasePattern(pattern []CapitalizationMode) Option {
+	return func(o *options) {
+		o.casePattern = nil
+		if len(pattern) > 0 {
+			o.casePattern = append([]CapitalizationMode{}, pattern...)
+		}
+		o.capitalize = nil
+	}
+}
+
+// caseMode returns the capitalization mode of word i (0-based): the
+// WithCasePattern entry in turn, or else the WithCapitalization mode.
+// CapAdaptive resolves to CapSentence if the words are separated and to
+// CapFirst otherwise, and CapSentence to CapFirst for the first word and
+// CapNone after.
+func (o *options) caseMode(i int) CapitalizationMode {
+	mode := o.capMode
+	if o.casePattern != nil {
+		mode = o.casePattern[i%len(o.casePattern)]
+	}
+	if mode == CapAdaptive {
+		mode = CapFirst
+		if o.separated() {
+			mode = CapSentence
+		}
+	}
+	if mode == CapSentence {
+		if i == 0 {
+			return CapFirst
+		}
+		return CapNone
+	}
+	return mode
+}
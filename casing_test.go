@@ -0,0 +1,217 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestWithCapitalization(t *testing.T) {
+	tests := []struct {
+		mode CapitalizationMode
+		want func(word string) string
+	}{
+		{CapFirst, capitalize},
+		{CapNone, strings.ToLower},
+		{CapUpper, strings.ToUpper},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.mode.String(), func(t *testing.T) {
+			_, words, _, err := generate(6, newOptions(WithCapitalization(tt.mode)))
+			if err != nil {
+				t.Fatalf("generate() error = %v", err)
+			}
+			for _, w := range words {
+				if w != tt.want(strings.ToLower(w)) {
+					t.Errorf("word %q is not cased with %v", w, tt.mode)
+				}
+			}
+			if got := EntropyWithOptions(6, WithCapitalization(tt.mode)); got != Entropy(6) {
+				t.Errorf("EntropyWithOptions() = %f, want %f", got, Entropy(6))
+			}
+		})
+	}
+
+	if _, err := GenerateWithOptions(4, WithCapitalization(CapitalizationMode(99))); err == nil {
+		t.Error("an unknown capitalization mode should return an error")
+	}
+	if got := CapitalizationMode(99).String(); got != "CapitalizationMode(99)" {
+		t.Errorf("String() = %q", got)
+	}
+
+	// The later of WithCapitalizer and WithCapitalization wins
+	_, words, _, _ := generate(3, newOptions(WithCapitalizer(strings.ToUpper), WithCapitalization(CapNone)))
+	for _, w := range words {
+		if w != strings.ToLower(w) {
+			t.Errorf("word %q should be lowercase", w)
+		}
+	}
+}
+
+func TestCapSentence(t *testing.T) {
+	o := newOptions(WithCapitalization(CapSentence), WithSeparator(" "))
+	passphrase, words, _, err := generate(5, o)
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if words[0] != capitalize(words[0]) {
+		t.Errorf("first word %q should be capitalized", words[0])
+	}
+	for _, w := range words[1:] {
+		if w != strings.ToLower(w) {
+			t.Errorf("word %q should be lowercase", w)
+		}
+	}
+	if want := strings.ToLower(passphrase); strings.ToLower(passphrase[:1])+passphrase[1:] != want {
+		t.Errorf("passphrase %q should be lowercase after its first letter", passphrase)
+	}
+	if got := EntropyWithOptions(5, WithCapitalization(CapSentence)); got != Entropy(5) {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, Entropy(5))
+	}
+
+	// In a pattern it applies to the first word of the passphrase only
+	_, words, _, _ = generate(4, newOptions(WithCasePattern([]CapitalizationMode{CapSentence, CapUpper})))
+	if words[0] != capitalize(strings.ToLower(words[0])) || words[2] != strings.ToLower(words[2]) || words[3] != strings.ToUpper(words[3]) {
+		t.Errorf("words = %q, want Capitalized, UPPER, lower, UPPER", words)
+	}
+}
+
+func TestCapAdaptive(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		opt  Option
+		want []string
+	}{
+		{"space", WithSeparator(" "), []string{"Colt", "default", "arousal"}},
+		{"random separators", WithSeparatorRandom([]string{"-", "."}), []string{"Colt", "default", "arousal"}},
+		{"no separator", WithSeparator(""), []string{"Colt", "Default", "Arousal"}},
+		{"empty separators", WithSeparators([]string{"", ""}), []string{"Colt", "Default", "Arousal"}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions(WithCapitalization(CapAdaptive), tt.opt)
+			for i, word := range []string{"colt", "default", "arousal"} {
+				if got, _ := o.applyCase(word, i); got != tt.want[i] {
+					t.Errorf("word %d = %q, want %q", i, got, tt.want[i])
+				}
+			}
+		})
+	}
+
+	passphrase, err := GenerateWithOptions(4, WithCapitalization(CapAdaptive), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	if rest := passphrase[1:]; rest != strings.ToLower(rest) {
+		t.Errorf("passphrase %q should be lowercase after its first letter", passphrase)
+	}
+	if got := EntropyWithOptions(5, WithCapitalization(CapAdaptive)); got != Entropy(5) {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, Entropy(5))
+	}
+}
+
+func TestCapRandom(t *testing.T) {
+	var capped, lower int
+	for i := 0; i < 20; i++ {
+		_, words, _, err := generate(6, newOptions(WithCapitalization(CapRandom)))
+		if err != nil {
+			t.Fatalf("generate() error = %v", err)
+		}
+		for _, w := range words {
+			switch w {
+			case capitalize(w):
+				capped++
+			case strings.ToLower(w):
+				lower++
+			default:
+				t.Errorf("word %q is neither capitalized nor lowercase", w)
+			}
+		}
+	}
+	// 120 fair coin flips all landing the same way is practically impossible
+	if capped == 0 || lower == 0 {
+		t.Errorf("got %d capitalized and %d lowercase words, want both", capped, lower)
+	}
+
+	// Every English word has a letter to capitalize
+	b := EntropyBreakdownWithOptions(6, WithCapitalization(CapRandom))
+	if b.Casing != 6 || b.Total != Entropy(6)+6 {
+		t.Errorf("breakdown = %+v, want 6 bits of casing", b)
+	}
+	// Some Reinhold words, like "1984" or "@", have none
+	if got := EntropyBreakdownWithOptions(6, WithLanguage(LanguageReinhold), WithCapitalization(CapRandom)).Casing; got <= 5 || got >= 6 {
+		t.Errorf("Reinhold casing entropy = %f, want a little under 6", got)
+	}
+	// A WithCapitalizer function takes over from the mode
+	if got := EntropyBreakdownWithOptions(6, WithCapitalization(CapRandom), WithCapitalizer(strings.ToUpper)).Casing; got != 0 {
+		t.Errorf("casing entropy with a capitalizer = %f, want 0", got)
+	}
+}
+
+func TestWithCasePattern(t *testing.T) {
+	pattern := []CapitalizationMode{CapFirst, CapNone, CapUpper}
+	_, words, _, err := generate(7, newOptions(WithCasePattern(pattern)))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for i, w := range words {
+		if mode := pattern[i%len(pattern)]; w != mode.apply(w) {
+			t.Errorf("word %d %q is not cased with %v", i, w, mode)
+		}
+	}
+
+	tests := []struct {
+		opts   []Option
+		casing float64
+	}{
+		{[]Option{WithCasePattern(pattern)}, 0},
+		{[]Option{WithCasePattern([]CapitalizationMode{CapRandom, CapFirst})}, 3},
+		{[]Option{WithCasePattern([]CapitalizationMode{CapUpper, CapRandom, CapRandom})}, 4},
+		{[]Option{WithCapitalization(CapRandom), WithCasePattern(nil)}, 6},
+		{[]Option{WithCasePattern([]CapitalizationMode{CapRandom}), WithCapitalization(CapFirst)}, 0},
+		{[]Option{WithCasePattern([]CapitalizationMode{CapRandom}), WithCapitalizer(strings.ToUpper)}, 0},
+	}
+	for i, tt := range tests {
+		if got := EntropyBreakdownWithOptions(6, tt.opts...).Casing; got != tt.casing {
+			t.Errorf("case %d: casing entropy = %f, want %f", i, got, tt.casing)
+		}
+	}
+
+	if _, err := GenerateWithOptions(4, WithCasePattern([]CapitalizationMode{CapFirst, 99})); err == nil {
+		t.Error("an unknown mode in the pattern should return an error")
+	}
+}
+
+func TestParseCapitalizationMode(t *testing.T) {
+	for _, mode := range []CapitalizationMode{CapFirst, CapNone, CapUpper, CapRandom, CapSentence, CapAdaptive} {
+		got, err := ParseCapitalizationMode(strings.ToUpper(mode.String()))
+		if err != nil || got != mode {
+			t.Errorf("ParseCapitalizationMode(%q) = %v, %v, want %v", mode, got, err, mode)
+		}
+	}
+	if got, err := ParseCapitalizationMode("lower"); err != nil || got != CapNone {
+		t.Errorf("ParseCapitalizationMode(lower) = %v, %v, want none", got, err)
+	}
+	if _, err := ParseCapitalizationMode("title"); err == nil {
+		t.Error("ParseCapitalizationMode(title) should return an error")
+	}
+}
+
+func TestGenerateWithRollsAndOptions(t *testing.T) {
+	passphrase, rolls, err := GenerateWithRollsAndOptions(4, WithCapitalization(CapNone), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithRollsAndOptions() error = %v", err)
+	}
+	words := strings.Split(passphrase, " ")
+	if len(words) != 4 || len(rolls) != 4 {
+		t.Fatalf("got %d words and %d rolls, want 4 each", len(words), len(rolls))
+	}
+	for i, roll := range rolls {
+		want, err := WordAt(roll, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("WordAt(%q) error = %v", roll, err)
+		}
+		if words[i] != strings.ToLower(want) {
+			t.Errorf("word %d = %q, want %q for roll %s", i, words[i], strings.ToLower(want), roll)
+		}
+	}
+}
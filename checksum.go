@@ -0,0 +1,111 @@
+package diceware
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// GenerateWithChecksum creates a passphrase with the specified number of
+// words plus a separate checksum word derived from them, for written-down
+// backups: re-deriving the checksum with VerifyChecksum after copying the
+// passphrase by hand catches most transcription errors.
+//
+// The checksum is a function of the passphrase, so it adds no entropy; the
+// passphrase's strength is still EntropyForLanguage(wordCount, lang). It is
+// returned on its own so it can be written down apart from the secret and
+// never ends up as part of it. For LanguageMixed the checksum word comes
+// from the English wordlist.
+func GenerateWithChecksum(wordCount int, lang Language) (passphrase, checksum string, err error) {
+	words, err := GenerateWords(wordCount, lang)
+	if err != nil {
+		return "", "", err
+	}
+	checksum, err = checksumWord(words, lang)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.Join(words, ""), checksum, nil
+}
+
+// VerifyChecksum reports whether checksum is the checksum word
+// GenerateWithChecksum derives for passphrase. The passphrase may use any
+// non-letter separator, see VerifyPassphrase; the checksum word is compared
+// case-insensitively.
+//
+// Like VerifyPassphrase, it splits the passphrase at capital letters, and
+// matches it against the wordlist when that doesn't work, e.g. for a
+// lowercased passphrase. Words with no letter to capitalize, like
+// Reinhold's "100" or "-", can make a passphrase split more than one way
+// ("19" or "1" and "9"); it is accepted if any of them gives checksum, up
+// to maxChecksumSplits of them.
+func VerifyChecksum(passphrase, checksum string, lang Language) (bool, error) {
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return false, err
+	}
+	checksum = strings.TrimSpace(checksum)
+	tried, found := 0, false
+	matches := func(words []string) bool {
+		tried++
+		var want string
+		if want, err = checksumWord(words, lang); err != nil {
+			return true
+		}
+		found = strings.EqualFold(checksum, want)
+		return found || tried == maxChecksumSplits
+	}
+
+	// Words as GenerateWithChecksum joins them, with no separators, then
+	// with separators if the passphrase can't be split without
+	capitalizedSplits(passphrase, lists, false, matches)
+	if tried == 0 {
+		capitalizedSplits(passphrase, lists, true, matches)
+	}
+	if tried == 0 {
+		if words, ok := segment(passphrase, lists, 0); ok {
+			matches(words)
+		}
+	}
+	if err != nil {
+		return false, err
+	}
+	return found, nil
+}
+
+// maxChecksumSplits bounds the ways of splitting a passphrase
+// VerifyChecksum tries. Generated passphrases need far fewer: at most a
+// few hundred for 8 Reinhold words, nearly always one.
+const maxChecksumSplits = 1000
+
+// checksumWord hashes the lowercased words with SHA-256 and reduces the
+// hash to an index into the usable words of lang's (first) wordlist, in
+// roll order. Hashing the words rather than the joined passphrase makes
+// the checksum independent of the separator.
+func checksumWord(words []string, lang Language) (string, error) {
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return "", err
+	}
+	wl := lists[0]
+
+	h := sha256.New()
+	for _, word := range words {
+		h.Write([]byte(strings.ToLower(word)))
+		h.Write([]byte{0})
+	}
+	sum := h.Sum(nil)
+	index := binary.BigEndian.Uint64(sum[:8]) % uint64(wl.Size())
+
+	for _, word := range wl.words {
+		if word == "" || !wl.accepts(word) {
+			continue
+		}
+		if index == 0 {
+			return capitalize(word), nil
+		}
+		index--
+	}
+	return "", fmt.Errorf("%s wordlist has no usable words", wl.name)
+}
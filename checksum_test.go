@@ -0,0 +1,81 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateWithChecksum(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		passphrase, checksum, err := GenerateWithChecksum(6, lang)
+		if err != nil {
+			t.Fatalf("GenerateWithChecksum(6, %v) error = %v", lang, err)
+		}
+		if ok, _ := VerifyPassphrase(passphrase, 6, lang); !ok {
+			t.Errorf("passphrase %q doesn't have 6 words", passphrase)
+		}
+
+		ok, err := VerifyChecksum(passphrase, checksum, lang)
+		if err != nil || !ok {
+			t.Errorf("VerifyChecksum(%q, %q) = %v, %v, want true", passphrase, checksum, ok, err)
+		}
+
+		// Separators and case don't matter
+		words, _ := splitCapitalized(passphrase)
+		if ok, _ := VerifyChecksum(strings.Join(words, " "), strings.ToLower(checksum), lang); !ok {
+			t.Errorf("VerifyChecksum() with separators and a lowercase checksum = false, want true")
+		}
+	}
+}
+
+func TestVerifyChecksumDetectsErrors(t *testing.T) {
+	words := []string{"Colt", "Default", "Arousal", "Thimble"}
+	checksum, err := checksumWord(words, LanguageEnglish)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := checksumWord(words, LanguageEnglish); again != checksum {
+		t.Errorf("checksumWord() is not deterministic: %q vs %q", checksum, again)
+	}
+
+	changes := [][]string{
+		{"Colt", "Default", "Arousal", "Thimbles"},
+		{"Default", "Colt", "Arousal", "Thimble"},
+		{"Colt", "Default", "Arousal"},
+	}
+	for _, changed := range changes {
+		if got, _ := checksumWord(changed, LanguageEnglish); got == checksum {
+			t.Errorf("checksumWord(%v) = %q, same as the original", changed, got)
+		}
+	}
+
+	if ok, _ := VerifyChecksum("ColtDefaultArousalThimble", checksum+"x", LanguageEnglish); ok {
+		t.Error("VerifyChecksum() with a wrong checksum word = true, want false")
+	}
+	if _, err := VerifyChecksum("ColtDefault", checksum, Language(99)); err == nil {
+		t.Error("VerifyChecksum() with an unsupported language should return an error")
+	}
+}
+
+func TestVerifyChecksumReinhold(t *testing.T) {
+	// Reinhold words like "100" have no letter to capitalize, so the
+	// passphrase can't always be split on capital letters
+	for _, passphrase := range []string{"Hess100DittyBbRudyAim", "Hess 100 Ditty Bb Rudy Aim", "hess100dittybbrudyaim"} {
+		if ok, err := VerifyChecksum(passphrase, "U's", LanguageReinhold); err != nil || !ok {
+			t.Errorf("VerifyChecksum(%q, %q) = %v, %v, want true", passphrase, "U's", ok, err)
+		}
+	}
+	if ok, _ := VerifyChecksum("Hess1000DittyBbRudyAim", "U's", LanguageReinhold); ok {
+		t.Error("VerifyChecksum() with a changed number word = true, want false")
+	}
+
+	for i := 0; i < 2000; i++ {
+		passphrase, checksum, err := GenerateWithChecksum(6, LanguageReinhold)
+		if err != nil {
+			t.Fatalf("GenerateWithChecksum() error = %v", err)
+		}
+		if ok, err := VerifyChecksum(passphrase, checksum, LanguageReinhold); err != nil || !ok {
+			t.Fatalf("VerifyChecksum(%q, %q) = %v, %v, want true", passphrase, checksum, ok, err)
+		}
+	}
+}
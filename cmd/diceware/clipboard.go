@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands lists, per platform, the commands that copy standard
+// input to the system clipboard, in order of preference.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+}
+
+// unixClipboardCommands covers Linux and the BSDs: Wayland first, then X11.
+var unixClipboardCommands = [][]string{
+	{"wl-copy"},
+	{"xclip", "-selection", "clipboard"},
+	{"xsel", "--clipboard", "--input"},
+}
+
+// copyToClipboard writes text to the system clipboard using the first
+// available platform tool, so the passphrase never appears on the
+// terminal.
+func copyToClipboard(text string) error {
+	candidates, ok := clipboardCommands[runtime.GOOS]
+	if !ok {
+		candidates = unixClipboardCommands
+	}
+
+	for _, args := range candidates {
+		path, err := exec.LookPath(args[0])
+		if err != nil {
+			continue
+		}
+		cmd := exec.Command(path, args[1:]...)
+		cmd.Stdin = strings.NewReader(text)
+		return cmd.Run()
+	}
+
+	var names []string
+	for _, args := range candidates {
+		names = append(names, args[0])
+	}
+	return errors.New("no clipboard tool found (looked for " + strings.Join(names, ", ") +
+		"); run without --copy to print the passphrase instead")
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/spf13/cobra"
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <list-a> <list-b>",
+	Short: "Show the words that differ between two wordlists",
+	Long: `Compare the words of two wordlists and print those only the first has,
+prefixed with "-", and those only the second has, prefixed with "+", like
+diff. Use it to check that an update to a wordlist didn't drop or change
+entries by accident.
+
+Each list is a wordlist file ("<roll> <word>" per line) or, if no such
+file exists, a built-in language code such as en or ro. Words are compared
+exactly; a word that only moved to a different roll isn't reported. Exits
+with status 1 if the lists differ.`,
+	Example: `  # Compare an updated Romanian list with the built-in one
+  diceware diff ro ro_diceware_new.txt`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := openWordlist(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := openWordlist(args[1])
+		if err != nil {
+			return err
+		}
+
+		onlyA, onlyB := diceware.DiffWordlists(a, b)
+		for _, word := range onlyA {
+			fmt.Println("-", word)
+		}
+		for _, word := range onlyB {
+			fmt.Println("+", word)
+		}
+		if len(onlyA) > 0 || len(onlyB) > 0 {
+			return fmt.Errorf("the wordlists differ: %d words only in %s, %d only in %s",
+				len(onlyA), args[0], len(onlyB), args[1])
+		}
+		fmt.Println("The wordlists have the same words.")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+}
+
+// openWordlist loads the wordlist file at arg, or the built-in list of the
+// language arg names if there is no such file.
+func openWordlist(arg string) (*diceware.Wordlist, error) {
+	f, err := os.Open(arg)
+	if os.IsNotExist(err) {
+		if info, lerr := diceware.ParseLanguage(arg); lerr == nil {
+			return diceware.WordlistByLanguage(info.Language)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return diceware.LoadWordlist(filepath.Base(arg), f)
+}
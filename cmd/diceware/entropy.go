@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/spf13/cobra"
+)
+
+var (
+	entropyWords    int
+	entropyLanguage string
+	entropyCase     string
+)
+
+var entropyCmd = &cobra.Command{
+	Use:   "entropy",
+	Short: "Print the entropy of a configuration without generating a passphrase",
+	Long: `Print the bits of entropy a passphrase generated with the given settings
+would have, to compare configurations before committing to one. The total
+adds up every source of randomness: the words themselves and, with
+--case random, the casing of each word.`,
+	Example: `  # Entropy of 6 mixed English and Romanian words
+  diceware entropy -w 6 -l mixed
+
+  # The same with randomly capitalized words
+  diceware entropy -w 6 -l mixed --case random`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := diceware.ParseLanguage(entropyLanguage)
+		if err != nil {
+			return err
+		}
+		if entropyWords < minWords || entropyWords > maxWords {
+			return fmt.Errorf("word count must be between %d and %d", minWords, maxWords)
+		}
+
+		capMode, err := diceware.ParseCapitalizationMode(entropyCase)
+		if err != nil {
+			return err
+		}
+
+		b := diceware.EntropyBreakdownWithOptions(entropyWords,
+			diceware.WithLanguage(info.Language),
+			diceware.WithCapitalization(capMode),
+		)
+		fmt.Printf("%.1f bits, %s (%d words, %s wordlist)\n",
+			b.Total, diceware.EntropyRating(b.Total), entropyWords, info.Name)
+		if b.Casing > 0 {
+			fmt.Printf("  words:  %.1f bits\n  casing: %.1f bits\n", b.Words, b.Casing)
+		}
+		return nil
+	},
+}
+
+func init() {
+	entropyCmd.Flags().IntVarP(&entropyWords, "words", "w", defaultWords,
+		fmt.Sprintf("number of words in the passphrase (%d-%d)", minWords, maxWords))
+	entropyCmd.Flags().StringVarP(&entropyLanguage, "lang", "l", "en", "language: "+languageCodes())
+	entropyCmd.Flags().StringVar(&entropyCase, "case", "first", "word casing: first, none, upper, random, sentence, or adaptive")
+	rootCmd.AddCommand(entropyCmd)
+}
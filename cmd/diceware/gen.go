@@ -0,0 +1,349 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/cleonte/go-diceware/internal/qr"
+	"github.com/spf13/cobra"
+)
+
+const (
+	defaultWords = 6
+	minWords     = 1
+	maxWords     = 20
+
+	// weakBits is the entropy below which gen warns that the passphrase
+	// is weak, in bits.
+	weakBits = 70
+)
+
+var (
+	words     int
+	separator string
+	showRolls bool
+	syllables bool
+	language  string
+	jsonOut   bool
+	wordlist  string
+	level     string
+	prefixLen int
+	caseMode  string
+	copyOut   bool
+	qrOut     bool
+	cardOut   bool
+	connect   bool
+	noNewline bool
+	quiet     bool
+)
+
+// jsonExtras are the fields --json adds to the passphrase's own JSON
+// object (see Passphrase.MarshalJSON). Rolls and Syllables are only
+// populated when --rolls and --syllables are also set.
+type jsonExtras struct {
+	Rolls     []string `json:"rolls,omitempty"`
+	Syllables []string `json:"syllables,omitempty"`
+	Rating    string   `json:"rating"`
+	WordCount int      `json:"wordCount"`
+}
+
+var genCmd = &cobra.Command{
+	Use:   "gen",
+	Short: "Generate a passphrase (the default when no command is given)",
+	Long: `Generate a passphrase. Running diceware without a command does the same,
+with the same flags.`,
+	Example: `  # Generate a 6-word English passphrase
+  diceware gen
+
+  # Generate an 8-word Romanian passphrase with dashes
+  diceware gen -w 8 -l ro -s "-"`,
+	Args: cobra.NoArgs,
+	RunE: runGen,
+}
+
+func init() {
+	addGenFlags(genCmd)
+	genCmd.SetHelpTemplate(genCmd.HelpTemplate() + wordCountHelp)
+	rootCmd.AddCommand(genCmd)
+}
+
+// wordCountHelp is appended to the help of the commands that generate.
+const wordCountHelp = `
+Recommended word counts for different security levels:
+  4 words  - ~52 bits  - Minimum for low-value accounts
+  6 words  - ~78 bits  - Recommended for most accounts
+  8 words  - ~103 bits - High security accounts
+  12 words - ~155 bits - Cryptocurrency wallets (minimum)
+Use --level low/medium/high/paranoid to pick these automatically.
+
+For more information about Diceware:
+  https://theworld.com/~reinhold/diceware.html
+  https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
+  https://github.com/danciu/diceware.ro (Romanian wordlist)
+`
+
+// addGenFlags defines the generation flags on cmd. Both the root command
+// and gen get them, so plain "diceware -w 8" keeps working.
+func addGenFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.IntVarP(&words, "words", "w", defaultWords,
+		fmt.Sprintf("number of words in the passphrase (%d-%d)", minWords, maxWords))
+	f.StringVarP(&separator, "separator", "s", "", "separator between words (default: none)")
+	f.BoolVar(&connect, "connectors", false, `put a random connector word ("and", "the", "of", ...) between the words, e.g. with -s " "`)
+	f.BoolVarP(&showRolls, "rolls", "r", false, "show dice rolls used to generate passphrase")
+	f.BoolVar(&syllables, "syllables", false, "show the words split into syllables (wash-board) to help read them aloud (English wordlist only)")
+	f.StringVarP(&language, "lang", "l", "en", "language: "+languageCodes())
+	f.BoolVar(&jsonOut, "json", false, "print the result as a JSON object")
+	f.BoolVar(&copyOut, "copy", false, "copy the passphrase to the clipboard instead of printing it")
+	f.BoolVar(&qrOut, "qr", false, "show the passphrase as a QR code to scan with a phone instead of printing it")
+	f.BoolVar(&cardOut, "card", false, "print the passphrase as a numbered card with space for a hint, to print and keep safe")
+	f.BoolVarP(&noNewline, "no-newline", "n", false, "don't print a newline after the passphrase")
+	f.BoolVarP(&quiet, "quiet", "q", false, "don't print the entropy or weak passphrase warnings to stderr")
+	f.StringVar(&caseMode, "case", "first", "word casing: first (Colt), none (colt), upper (COLT), random (Colt or colt), sentence (only the first word capitalized), or adaptive (sentence with a separator, first without)")
+	f.StringVar(&level, "level", "", "security level: low, medium, high, or paranoid (sets the word count)")
+	f.StringVar(&wordlist, "wordlist", "", "generate from a custom Diceware wordlist file (overrides --lang)")
+	f.IntVar(&prefixLen, "check-prefixes", 0, "warn if --wordlist words aren't unique in their first N characters (requires --wordlist)")
+	addHashFlags(cmd)
+}
+
+func runGen(cmd *cobra.Command, args []string) error {
+	if prefixLen > 0 && wordlist == "" {
+		return fmt.Errorf("--check-prefixes requires --wordlist")
+	}
+
+	// Parse language
+	var lang diceware.Language
+	var langName string
+	switch {
+	case wordlist != "":
+		var err error
+		if lang, err = loadWordlist(wordlist); err != nil {
+			return err
+		}
+		if prefixLen > 0 {
+			wl, _ := diceware.WordlistByLanguage(lang)
+			if err := wl.CheckUniquePrefixes(prefixLen); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+			}
+		}
+		langName = fmt.Sprintf("custom (%s)", wordlist)
+	default:
+		info, err := diceware.ParseLanguage(language)
+		if err != nil {
+			return err
+		}
+		lang, langName = info.Language, info.Name
+	}
+
+	if level != "" {
+		if cmd.Flags().Changed("words") {
+			return fmt.Errorf("--level and --words can't be used together")
+		}
+		l, err := diceware.ParseSecurityLevel(level)
+		if err != nil {
+			return err
+		}
+		if words, err = diceware.WordCountForLevel(l, lang); err != nil {
+			return err
+		}
+	}
+
+	// Validate word count
+	if words < minWords || words > maxWords {
+		return fmt.Errorf("word count must be between %d and %d", minWords, maxWords)
+	}
+
+	capMode, err := diceware.ParseCapitalizationMode(caseMode)
+	if err != nil {
+		return err
+	}
+	opts := []diceware.Option{
+		diceware.WithLanguage(lang),
+		diceware.WithCapitalization(capMode),
+	}
+
+	if syllables && lang != diceware.LanguageEnglish {
+		return fmt.Errorf("--syllables only supports the English wordlist")
+	}
+	if copyOut && (jsonOut || showRolls || syllables) {
+		// They would print the passphrase (or the rolls that reveal it)
+		return fmt.Errorf("--copy can't be combined with --json, --rolls or --syllables")
+	}
+	if qrOut && (copyOut || jsonOut || showRolls || syllables) {
+		return fmt.Errorf("--qr can't be combined with --copy, --json, --rolls or --syllables")
+	}
+	if cardOut && (copyOut || qrOut || jsonOut || showRolls || syllables) {
+		return fmt.Errorf("--card can't be combined with --copy, --qr, --json, --rolls or --syllables")
+	}
+	if hashAlg != "" && (qrOut || cardOut || jsonOut || showRolls || syllables) {
+		return fmt.Errorf("--hash can't be combined with --qr, --card, --json, --rolls or --syllables")
+	}
+	opts = append(opts, diceware.WithSeparator(separator), diceware.WithConnectorWords(connect))
+	entropy := diceware.EntropyWithOptions(words, opts...)
+	if entropy < weakBits && !quiet {
+		warnWeak(entropy, opts)
+	}
+	if jsonOut {
+		return printJSON(opts)
+	}
+	if hashAlg != "" {
+		return printHash(opts)
+	}
+	if cardOut {
+		p, err := diceware.GeneratePassphrase(words, opts...)
+		if err != nil {
+			return err
+		}
+		return diceware.FormatCard(p, os.Stdout)
+	}
+
+	// Generate passphrase
+	if showRolls || syllables {
+		res, err := diceware.GenerateDetailed(words, opts...)
+		if err != nil {
+			return err
+		}
+
+		if showRolls {
+			var rolls []string
+			for _, w := range res.Rolled {
+				rolls = append(rolls, w.Roll)
+			}
+			if lang == diceware.LanguageBIP39English {
+				fmt.Println("Word indexes:", rolls)
+			} else {
+				fmt.Println("Dice rolls:", rolls)
+			}
+		}
+		if syllables {
+			fmt.Println("Syllables:", strings.Join(syllableHints(res.Words), " "))
+		}
+		fmt.Print("Passphrase: ")
+		printPassphrase(res.Passphrase)
+	} else {
+		passphrase, err := diceware.GenerateWithOptions(words, opts...)
+		if err != nil {
+			return err
+		}
+
+		if copyOut {
+			if err := copyToClipboard(passphrase); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stderr, "Passphrase copied to clipboard.")
+		} else if qrOut {
+			code, err := qr.Encode(passphrase)
+			if err != nil {
+				return err
+			}
+			fmt.Print(code)
+		} else {
+			printPassphrase(passphrase)
+		}
+	}
+
+	// Show entropy information
+	if !quiet {
+		fmt.Fprintf(os.Stderr, "\nEntropy: %.1f bits, %s (%d words, %s wordlist)\n",
+			entropy, diceware.EntropyRating(entropy), words, langName)
+	}
+
+	return nil
+}
+
+// warnWeak explains on stderr that a passphrase of entropy bits is weak
+// and suggests the word count that reaches weakBits with the same options.
+func warnWeak(entropy float64, opts []diceware.Option) {
+	fmt.Fprintf(os.Stderr, "Warning: %d words give only %.1f bits of entropy, below the recommended %d.\n",
+		words, entropy, weakBits)
+	fmt.Fprintln(os.Stderr, "Passphrases this short are within reach of offline attacks on leaked password hashes.")
+	for n := words + 1; n <= maxWords; n++ {
+		if diceware.EntropyWithOptions(n, opts...) >= weakBits {
+			fmt.Fprintf(os.Stderr, "Use at least %d words (-w %d), or --quiet to silence this warning.\n", n, n)
+			return
+		}
+	}
+}
+
+// printPassphrase writes the passphrase to stdout, followed by a newline
+// unless --no-newline is set.
+func printPassphrase(passphrase string) {
+	if noNewline {
+		fmt.Print(passphrase)
+		return
+	}
+	fmt.Println(passphrase)
+}
+
+// loadWordlist reads the --wordlist file and registers it as a language, so
+// the rest of run can treat it like a built-in one. Parse errors carry the
+// offending line number.
+func loadWordlist(path string) (diceware.Language, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return diceware.RegisterLanguage(filepath.Base(path), f)
+}
+
+// printJSON generates the passphrase with GeneratePassphrase and writes
+// its JSON object to stdout with the jsonExtras fields added, so the CLI
+// and library JSON have the same shape. The rolls are collected with
+// WithRollObserver, which starts over at word 0 whenever a passphrase is
+// discarded.
+func printJSON(opts []diceware.Option) error {
+	var rolls []string
+	if showRolls {
+		opts = append(opts, diceware.WithRollObserver(func(i int, roll, _ string) {
+			rolls = append(rolls[:i], roll)
+		}))
+	}
+	p, err := diceware.GeneratePassphrase(words, opts...)
+	if err != nil {
+		return err
+	}
+	extras := jsonExtras{
+		Rolls:     rolls,
+		Rating:    diceware.EntropyRating(p.Entropy()),
+		WordCount: words,
+	}
+	if syllables {
+		extras.Syllables = syllableHints(p.Words())
+	}
+
+	base, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	more, err := json.Marshal(extras)
+	if err != nil {
+		return err
+	}
+	// Both are objects: join them into one
+	joined := append(append(base[:len(base)-1], ','), more[1:]...)
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, joined, "", "  "); err != nil {
+		return err
+	}
+	out.WriteByte('\n')
+	_, err = out.WriteTo(os.Stdout)
+	return err
+}
+
+// syllableHints returns each of words split into syllables for --syllables,
+// e.g. "Wash-board".
+func syllableHints(words []string) []string {
+	hints := make([]string, len(words))
+	for i, w := range words {
+		hints[i] = diceware.SyllableHint(w)
+	}
+	return hints
+}
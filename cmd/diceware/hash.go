@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/cleonte/go-diceware/internal/kdf"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultHashIterations is the --hash-iterations default, OWASP's
+	// recommendation for PBKDF2-HMAC-SHA256.
+	defaultHashIterations = 600000
+	// hashSaltLen is the length of the random salt in bytes.
+	hashSaltLen = 16
+)
+
+var (
+	hashAlg        string
+	hashIterations int
+	hashSalt       string
+)
+
+// addHashFlags defines the --hash flags on cmd, see addGenFlags.
+func addHashFlags(cmd *cobra.Command) {
+	f := cmd.Flags()
+	f.StringVar(&hashAlg, "hash", "", "print a key derived from the passphrase instead of the passphrase, which goes to stderr: pbkdf2")
+	f.IntVar(&hashIterations, "hash-iterations", defaultHashIterations, "PBKDF2 iterations for --hash")
+	f.StringVar(&hashSalt, "hash-salt", "", "salt for --hash, in hex (default: 16 random bytes)")
+}
+
+// printHash generates a passphrase and prints its PBKDF2-HMAC-SHA256 key
+// to stdout as a PHC string, to store instead of the passphrase. The
+// passphrase itself goes to stderr, or to the clipboard with --copy, so
+// that redirecting stdout keeps only the key.
+func printHash(opts []diceware.Option) error {
+	if !strings.EqualFold(hashAlg, "pbkdf2") {
+		return fmt.Errorf("unsupported --hash %q: only pbkdf2 is available without dependencies outside the standard library", hashAlg)
+	}
+	if hashIterations < 1 {
+		return fmt.Errorf("--hash-iterations must be at least 1")
+	}
+	var salt []byte
+	if hashSalt != "" {
+		var err error
+		if salt, err = hex.DecodeString(hashSalt); err != nil {
+			return fmt.Errorf("invalid --hash-salt: %w", err)
+		}
+	} else {
+		salt = make([]byte, hashSaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return fmt.Errorf("failed to generate salt: %w", err)
+		}
+	}
+
+	passphrase, err := diceware.GenerateWithOptions(words, opts...)
+	if err != nil {
+		return err
+	}
+	if copyOut {
+		if err := copyToClipboard(passphrase); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Passphrase copied to clipboard.")
+	} else {
+		fmt.Fprintln(os.Stderr, "Passphrase:", passphrase)
+	}
+	fmt.Println(kdf.PBKDF2SHA256([]byte(passphrase), salt, hashIterations))
+	return nil
+}
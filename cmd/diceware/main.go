@@ -3,31 +3,21 @@ package main
 import (
 	"fmt"
 	"os"
+	"strings"
 
 	"github.com/cleonte/go-diceware"
 	"github.com/spf13/cobra"
 )
 
-const (
-	defaultWords = 6
-	minWords     = 1
-	maxWords     = 20
-)
-
-var (
-	words     int
-	separator string
-	showRolls bool
-	language  string
-)
-
 var rootCmd = &cobra.Command{
 	Use:   "diceware",
 	Short: "Diceware Passphrase Generator",
 	Long: `Generate cryptographically secure passphrases using the Diceware method
 with the EFF large wordlist (7,776 English words) or Romanian wordlist (7,776 words).
 
-Words are capitalized and concatenated by default (like "ColtDefaultArousal").`,
+Words are capitalized and concatenated by default (like "ColtDefaultArousal").
+
+Without a command, diceware generates a passphrase like "diceware gen".`,
 	Example: `  # Generate a 6-word English passphrase (default, no separator)
   diceware
   Output: ColtDefaultArousalThimbleGaslightYearbook
@@ -55,82 +45,42 @@ Words are capitalized and concatenated by default (like "ColtDefaultArousal").`,
   diceware -r
 
   # Generate 10-word Romanian passphrase with underscores
-  diceware -w 10 -l ro -s "_"`,
-	RunE:          run,
+  diceware -w 10 -l ro -s "_"
+
+  # Print a JSON object for scripting (add -r to include the dice rolls)
+  diceware --json -r
+
+  # Lowercase words for password fields that reject upper case
+  diceware --case none -s "-"
+  Output: colt-default-arousal-thimble
+
+  # Copy the passphrase to the clipboard instead of printing it
+  diceware --copy
+
+  # Pick the word count for a security level (low, medium, high, paranoid)
+  diceware --level high
+
+  # Generate from your own wordlist file ("<roll> <word>" per line)
+  diceware --wordlist my_wordlist.txt`,
+	RunE:          runGen,
 	SilenceUsage:  true,
 	SilenceErrors: true,
 }
 
 func init() {
-	rootCmd.Flags().IntVarP(&words, "words", "w", defaultWords,
-		fmt.Sprintf("number of words in the passphrase (%d-%d)", minWords, maxWords))
-	rootCmd.Flags().StringVarP(&separator, "separator", "s", "", "separator between words (default: none)")
-	rootCmd.Flags().BoolVarP(&showRolls, "rolls", "r", false, "show dice rolls used to generate passphrase")
-	rootCmd.Flags().StringVarP(&language, "lang", "l", "en", "language: en (English), ro (Romanian), or mixed")
-
-	rootCmd.SetHelpTemplate(rootCmd.HelpTemplate() + fmt.Sprintf(`
-Recommended word counts for different security levels:
-  4 words  - ~52 bits  - Minimum for low-value accounts
-  6 words  - ~78 bits  - Recommended for most accounts
-  8 words  - ~103 bits - High security accounts
-  12 words - ~155 bits - Cryptocurrency wallets (minimum)
-
-For more information about Diceware:
-  https://theworld.com/~reinhold/diceware.html
-  https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases
-  https://github.com/danciu/diceware.ro (Romanian wordlist)
-`))
+	addGenFlags(rootCmd)
+	rootCmd.SetHelpTemplate(rootCmd.HelpTemplate() + wordCountHelp)
 }
 
-func run(cmd *cobra.Command, args []string) error {
-	// Validate word count
-	if words < minWords || words > maxWords {
-		return fmt.Errorf("word count must be between %d and %d", minWords, maxWords)
-	}
-
-	// Parse language
-	var lang diceware.Language
-	switch language {
-	case "en", "english":
-		lang = diceware.LanguageEnglish
-	case "ro", "romanian":
-		lang = diceware.LanguageRomanian
-	case "mixed", "mix":
-		lang = diceware.LanguageMixed
-	default:
-		return fmt.Errorf("unsupported language '%s'. Use: en, ro, or mixed", language)
-	}
-
-	// Generate passphrase
-	if showRolls {
-		passphrase, rolls, err := diceware.GenerateWithRollsLanguageAndSeparator(words, lang, separator)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println("Dice rolls:", rolls)
-		fmt.Println("Passphrase:", passphrase)
-	} else {
-		passphrase, err := diceware.GenerateWithLanguageAndSeparator(words, lang, separator)
-		if err != nil {
-			return err
-		}
-
-		fmt.Println(passphrase)
-	}
-
-	// Show entropy information
-	entropy := diceware.EntropyForLanguage(words, lang)
-	langName := "English"
-	if lang == diceware.LanguageRomanian {
-		langName = "Romanian"
-	} else if lang == diceware.LanguageMixed {
-		langName = "Mixed (English + Romanian)"
+// languageCodes lists the --lang values for the flag help, e.g. "en, ro,
+// mixed or reinhold".
+func languageCodes() string {
+	var codes []string
+	for _, info := range diceware.ListLanguages() {
+		codes = append(codes, info.Code)
 	}
-	fmt.Fprintf(os.Stderr, "\nEntropy: %.1f bits (%d words, %s wordlist)\n",
-		entropy, words, langName)
-
-	return nil
+	last := len(codes) - 1
+	return strings.Join(codes[:last], ", ") + " or " + codes[last]
 }
 
 func main() {
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"os"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/spf13/cobra"
+)
+
+var sheetLanguage string
+
+var sheetCmd = &cobra.Command{
+	Use:   "sheet",
+	Short: "Print the full roll-to-word table for rolling physical dice offline",
+	Long: `Print all 7,776 dice rolls of a wordlist with their words, one
+"<roll>	<word>" line each, sorted from 11111 to 66666. Print it and roll five
+physical dice per word to make a passphrase without trusting any computer.
+
+Rolls marked "(roll again)" have no usable word; roll all five dice again.`,
+	Example: `  # Print the English sheet
+  diceware sheet > english.txt
+
+  # Print the Romanian sheet
+  diceware sheet -l ro`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := diceware.ParseLanguage(sheetLanguage)
+		if err != nil {
+			return err
+		}
+		return diceware.PrintWordlist(os.Stdout, info.Language)
+	},
+}
+
+func init() {
+	sheetCmd.Flags().StringVarP(&sheetLanguage, "lang", "l", "en", "language: "+languageCodes()+" (mixed and bip39 have no dice sheet)")
+	rootCmd.AddCommand(sheetCmd)
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/cleonte/go-diceware"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifyWords    int
+	verifyLanguage string
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify [passphrase]",
+	Short: "Check that a passphrase is made of words from a wordlist",
+	Long: `Check that a passphrase has the expected number of words, each from the
+language's wordlist, e.g. to catch typos when copying one out by hand. Any
+separator between the words is accepted, and so is any capitalization:
+without capital letters or separators, the words are found by matching the
+passphrase against the wordlist.
+
+Without an argument the passphrase is read from the first line of standard
+input, which keeps it out of the shell history. Exits with status 1 if the
+passphrase doesn't match.`,
+	Example: `  # Check a 6-word English passphrase typed at the prompt
+  diceware verify
+
+  # Check a 4-word Romanian passphrase
+  echo "Aba-Abager-Abajur-Abataj" | diceware verify -w 4 -l ro`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		info, err := diceware.ParseLanguage(verifyLanguage)
+		if err != nil {
+			return err
+		}
+
+		var passphrase string
+		if len(args) == 1 {
+			passphrase = args[0]
+		} else if passphrase, err = readLine(); err != nil {
+			return err
+		}
+
+		ok, err := diceware.VerifyPassphrase(passphrase, verifyWords, info.Language)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("not a %d-word %s passphrase", verifyWords, info.Name)
+		}
+		fmt.Printf("OK: a %d-word %s passphrase\n", verifyWords, info.Name)
+		return nil
+	},
+}
+
+func init() {
+	verifyCmd.Flags().IntVarP(&verifyWords, "words", "w", defaultWords, "expected number of words")
+	verifyCmd.Flags().StringVarP(&verifyLanguage, "lang", "l", "en", "language: "+languageCodes())
+	rootCmd.AddCommand(verifyCmd)
+}
+
+// readLine reads the first line of standard input, without the line
+// ending.
+func readLine() (string, error) {
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("reading the passphrase from standard input: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
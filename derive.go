@@ -0,0 +1,165 @@
+package diceware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+)
+
+// minSeedLen is the shortest seed DeriveFromSeed accepts, 128 bits.
+const minSeedLen = 16
+
+// deriveSalt and deriveInfo are the HKDF salt and info of DeriveFromSeed.
+// They version the derivation: changing either, or how the expanded bytes
+// become rolls, changes every derived passphrase.
+const (
+	deriveSalt = "go-diceware DeriveFromSeed"
+	deriveInfo = "v1 dice rolls"
+)
+
+// DeriveFromSeed deterministically derives a passphrase of wordCount words
+// in lang from seed, a high-entropy secret such as a recovery seed: the
+// same seed always gives the same passphrase, on every platform and
+// version of this package, e.g. for deterministic account recovery words.
+//
+// Unlike the Generate functions this involves no randomness at all. The
+// passphrase is exactly as secret as seed, which must be at least 16 bytes
+// and should come from a CSPRNG; never derive from a password or other
+// guessable input. The words carry at most as much entropy as seed.
+//
+// The seed is expanded with HKDF-SHA256 (RFC 5869) into a byte stream.
+// Each die takes one byte, bytes 252-255 being skipped to keep the faces
+// uniform; for LanguageMixed one byte before the dice picks the list (even:
+// English, odd: Romanian); LanguageBIP39English takes two bytes per word
+// instead of dice, keeping their low 11 bits. Rolls without a usable word
+// are rolled again from the stream. Words are capitalized and joined
+// without a separator, like Generate. The stream has no length limit, so
+// neither has wordCount: past the 255 × 32 bytes one HKDF expansion
+// allows, it goes on with further expansions of the same key (see
+// hkdfReader).
+//
+// Returns an error if wordCount is less than 1, if seed is too short or if
+// lang is unsupported.
+func DeriveFromSeed(seed []byte, wordCount int, lang Language) (string, error) {
+	if wordCount < 1 {
+		return "", invalidWordCount(wordCount)
+	}
+	if len(seed) < minSeedLen {
+		return "", fmt.Errorf("seed must be at least %d bytes, got %d", minSeedLen, len(seed))
+	}
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return "", err
+	}
+
+	stream := newHKDF(seed, []byte(deriveSalt), []byte(deriveInfo))
+	var b strings.Builder
+	for i := 0; i < wordCount; i++ {
+		word, err := deriveWord(stream, lists)
+		if err != nil {
+			return "", fmt.Errorf("failed to derive word %d: %w", i+1, err)
+		}
+		b.WriteString(capitalize(word))
+	}
+	return b.String(), nil
+}
+
+// passphraseAtSeed is the fixed, public seed PassphraseAt derives from,
+// followed by the index.
+const passphraseAtSeed = "go-diceware PassphraseAt fixtures"
+
+// PassphraseAt returns passphrase number index of a fixed, endless sequence
+// of passphrases of wordCount words in lang: PassphraseAt(5, 6, lang)
+// returns the same value on every call, platform and version, for stable
+// test fixtures, demos and paginated listings that address passphrases by
+// position instead of storing them. Any index can be computed directly,
+// without generating the ones before it.
+//
+// The sequence is public, so NEVER use its passphrases as secrets. It is
+// DeriveFromSeed with a seed made of a fixed string and the index, so
+// passphrases are capitalized and joined without a separator, and a
+// longer passphrase at an index extends the shorter one. Returns an error
+// if wordCount is less than 1 or lang is unsupported.
+func PassphraseAt(index uint64, wordCount int, lang Language) (string, error) {
+	seed := binary.BigEndian.AppendUint64([]byte(passphraseAtSeed), index)
+	return DeriveFromSeed(seed, wordCount, lang)
+}
+
+// deriveWord reads one word of lists from the derivation stream r.
+func deriveWord(r io.Reader, lists []*Wordlist) (string, error) {
+	for {
+		list := lists[0]
+		if len(lists) > 1 {
+			pick, err := readByte(r)
+			if err != nil {
+				return "", err
+			}
+			// Reject the top bytes that would favor some lists
+			if int(pick) >= 256-256%len(lists) {
+				continue
+			}
+			list = lists[int(pick)%len(lists)]
+		}
+
+		i, err := list.rollEach(r)
+		if err != nil {
+			return "", err
+		}
+		if word := list.words[i]; word != "" && list.accepts(word) {
+			return word, nil
+		}
+	}
+}
+
+// hkdfReader is the output of HKDF-SHA256 (RFC 5869) as an endless
+// io.Reader: Extract runs once in newHKDF, and Read returns successive
+// bytes of Expand. An expansion is limited to 255 × 32 bytes, so once one
+// is used up the stream continues with the next segment, the expansion
+// of the same key with info followed by the segment number as 4
+// big-endian bytes (1, 2, ...). The first 255 × 32 bytes are plain HKDF.
+type hkdfReader struct {
+	expander hash.Hash
+	base     []byte // info of the first segment
+	info     []byte // info of the current segment
+	segment  uint32
+	prev     []byte // T(n), the last block
+	buf      []byte // unread part of prev
+	counter  byte
+}
+
+// newHKDF returns the HKDF-SHA256 output stream for secret, salt and info.
+func newHKDF(secret, salt, info []byte) *hkdfReader {
+	extractor := hmac.New(sha256.New, salt)
+	extractor.Write(secret)
+	prk := extractor.Sum(nil)
+	return &hkdfReader{expander: hmac.New(sha256.New, prk), base: info, info: info}
+}
+
+func (h *hkdfReader) Read(p []byte) (int, error) {
+	n := 0
+	for n < len(p) {
+		if len(h.buf) == 0 {
+			if h.counter == 255 {
+				h.segment++
+				h.info = binary.BigEndian.AppendUint32(append([]byte(nil), h.base...), h.segment)
+				h.prev = h.prev[:0]
+				h.counter = 0
+			}
+			h.counter++
+			h.expander.Reset()
+			h.expander.Write(h.prev)
+			h.expander.Write(h.info)
+			h.expander.Write([]byte{h.counter})
+			h.prev = h.expander.Sum(h.prev[:0])
+			h.buf = h.prev
+		}
+		c := copy(p[n:], h.buf)
+		h.buf = h.buf[c:]
+		n += c
+	}
+	return n, nil
+}
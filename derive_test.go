@@ -0,0 +1,164 @@
+package diceware
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"strings"
+	"testing"
+)
+
+// TestHKDF checks the HKDF stream against RFC 5869 test case 1.
+func TestHKDF(t *testing.T) {
+	ikm := bytes.Repeat([]byte{0x0b}, 22)
+	salt, _ := hex.DecodeString("000102030405060708090a0b0c")
+	info, _ := hex.DecodeString("f0f1f2f3f4f5f6f7f8f9")
+	want := "3cb25f25faacd57a90434f64d0362f2a2d2d0a90cf1a5a4c5db02d56ecc4c5bf34007208d5b887185865"
+
+	// Read in uneven pieces to cross the block boundary
+	var okm []byte
+	r := newHKDF(ikm, salt, info)
+	for _, n := range []int{1, 30, 11} {
+		piece := make([]byte, n)
+		if _, err := io.ReadFull(r, piece); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		okm = append(okm, piece...)
+	}
+	if got := hex.EncodeToString(okm); got != want {
+		t.Errorf("HKDF output = %s, want %s", got, want)
+	}
+
+	// Past one expansion, the stream goes on with the expansion for info
+	// followed by the segment number
+	long := make([]byte, 2*255*32+32)
+	if _, err := io.ReadFull(newHKDF(ikm, salt, info), long); err != nil {
+		t.Fatalf("Read() past 255 blocks error = %v", err)
+	}
+	for segment := byte(1); segment <= 2; segment++ {
+		next := make([]byte, 32)
+		io.ReadFull(newHKDF(ikm, salt, append(info, 0, 0, 0, segment)), next)
+		if got := long[int(segment)*255*32:][:32]; !bytes.Equal(got, next) {
+			t.Errorf("segment %d starts with %x, want %x", segment, got, next)
+		}
+	}
+}
+
+func TestDeriveFromSeedLong(t *testing.T) {
+	// 2000 English words take more than the 255 × 32 bytes of one HKDF
+	// expansion; the first ones don't depend on how many follow
+	seed := bytes.Repeat([]byte{0x42}, 32)
+	long, err := DeriveFromSeed(seed, 2000, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("DeriveFromSeed(2000 words) error = %v", err)
+	}
+	if ok, _ := VerifyPassphrase(long, 2000, LanguageEnglish); !ok {
+		t.Errorf("DeriveFromSeed(2000 words) isn't a passphrase of 2000 words")
+	}
+	short, _ := DeriveFromSeed(seed, 1500, LanguageEnglish)
+	if !strings.HasPrefix(long, short) {
+		t.Error("DeriveFromSeed(2000 words) doesn't extend DeriveFromSeed(1500 words)")
+	}
+}
+
+func TestDeriveFromSeed(t *testing.T) {
+	seed := bytes.Repeat([]byte{0x42}, 32)
+
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		a, err := DeriveFromSeed(seed, 6, lang)
+		if err != nil {
+			t.Fatalf("DeriveFromSeed(%v) error = %v", lang, err)
+		}
+		b, _ := DeriveFromSeed(seed, 6, lang)
+		if a != b {
+			t.Errorf("DeriveFromSeed(%v) gave %q, then %q", lang, a, b)
+		}
+		if ok, _ := VerifyPassphrase(a, 6, lang); !ok {
+			t.Errorf("DeriveFromSeed(%v) = %q, not a passphrase of 6 words", lang, a)
+		}
+	}
+
+	other := bytes.Repeat([]byte{0x43}, 32)
+	a, _ := DeriveFromSeed(seed, 6, LanguageEnglish)
+	if b, _ := DeriveFromSeed(other, 6, LanguageEnglish); a == b {
+		t.Errorf("different seeds derived the same passphrase %q", a)
+	}
+	// A longer passphrase extends the shorter one
+	if long, _ := DeriveFromSeed(seed, 8, LanguageEnglish); long[:len(a)] != a {
+		t.Errorf("DeriveFromSeed(8 words) = %q, want it to start with %q", long, a)
+	}
+
+	if _, err := DeriveFromSeed(seed[:15], 6, LanguageEnglish); err == nil {
+		t.Error("DeriveFromSeed() with a 15-byte seed should return an error")
+	}
+	if _, err := DeriveFromSeed(seed, 0, LanguageEnglish); err == nil {
+		t.Error("DeriveFromSeed() with 0 words should return an error")
+	}
+	if _, err := DeriveFromSeed(seed, 6, Language(99)); err == nil {
+		t.Error("DeriveFromSeed() with an unsupported language should return an error")
+	}
+}
+
+// TestDeriveFromSeedStable pins derived passphrases: they must never change,
+// or recovery words derived with an earlier version stop matching.
+func TestDeriveFromSeedStable(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		lang Language
+		want string
+	}{
+		{LanguageEnglish, "OppressorRerouteMoonstoneDisownKineticSprang"},
+		{LanguageRomanian, "MdaPiuiLezneDoarIradiaSmicea"},
+		{LanguageMixed, "EsternRobinLionHatchingSprangAiuri"},
+	}
+	for _, tt := range tests {
+		got, err := DeriveFromSeed(seed, 6, tt.lang)
+		if err != nil {
+			t.Fatalf("DeriveFromSeed(%v) error = %v", tt.lang, err)
+		}
+		if got != tt.want {
+			t.Errorf("DeriveFromSeed(%v) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+func TestPassphraseAt(t *testing.T) {
+	a, err := PassphraseAt(5, 6, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("PassphraseAt() error = %v", err)
+	}
+	if again, _ := PassphraseAt(5, 6, LanguageEnglish); again != a {
+		t.Errorf("PassphraseAt(5) = %q then %q, want the same passphrase", a, again)
+	}
+	if next, _ := PassphraseAt(6, 6, LanguageEnglish); next == a {
+		t.Errorf("PassphraseAt(5) and PassphraseAt(6) both = %q, want different passphrases", a)
+	}
+	seed := append([]byte(passphraseAtSeed), 0, 0, 0, 0, 0, 0, 0, 5)
+	if derived, _ := DeriveFromSeed(seed, 6, LanguageEnglish); derived != a {
+		t.Errorf("PassphraseAt(5) = %q, want DeriveFromSeed() = %q", a, derived)
+	}
+
+	// The sequence must never change, or fixtures built on it break.
+	tests := []struct {
+		index uint64
+		lang  Language
+		want  string
+	}{
+		{0, LanguageEnglish, "WashboardShakinessSadlyFlipFleshy"},
+		{1, LanguageEnglish, "PuncturedMagnifierGammaYankingDosage"},
+		{1, LanguageRomanian, "OptareKevlarGenDroaieUrcior"},
+		{1 << 63, LanguageEnglish, "ElongatedCandiedDrivableUnwomanlyGlazing"},
+	}
+	for _, tt := range tests {
+		if got, _ := PassphraseAt(tt.index, 5, tt.lang); got != tt.want {
+			t.Errorf("PassphraseAt(%d, 5, %v) = %q, want %q", tt.index, tt.lang, got, tt.want)
+		}
+	}
+
+	if _, err := PassphraseAt(0, 0, LanguageEnglish); err == nil {
+		t.Error("PassphraseAt() with 0 words should return an error")
+	}
+	if _, err := PassphraseAt(0, 6, Language(99)); err == nil {
+		t.Error("PassphraseAt() with an unsupported language should return an error")
+	}
+}
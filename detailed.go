@@ -0,0 +1,43 @@
+package diceware
+
+// Result describes a generated passphrase: GenerateDetailed returns one, and
+// GenerateStream sends one per passphrase.
+type Result struct {
+	// Passphrase is the generated passphrase.
+	Passphrase string
+	// Words are the words of the passphrase as shown, in order, including
+	// a WithNumberWord number.
+	Words []string
+	// Rolled says how each of Words was chosen: its dice roll and the
+	// language and wordlist it came from.
+	Rolled []RolledWord
+	// Entropy is the entropy of the passphrase in bits for the options
+	// used, decorations included (see EntropyWithOptions).
+	Entropy float64
+	// Err is the error that ended a GenerateStream stream, in which case
+	// the other fields are empty. GenerateDetailed returns its error
+	// separately and leaves Err nil.
+	Err error
+}
+
+// GenerateDetailed is like GenerateWithOptions but returns everything known
+// about the passphrase in one Result: the words, how each was rolled and
+// the entropy for the options used. Getting the entropy from the same
+// options as the passphrase keeps it from drifting from the actual settings,
+// as a separate Entropy call easily does once decorations are involved.
+//
+// Returns an error if wordCount is less than 1, if the options are invalid,
+// or if random number generation fails.
+func GenerateDetailed(wordCount int, opts ...Option) (Result, error) {
+	o := newOptions(opts...)
+	passphrase, words, rolled, err := generate(wordCount, o)
+	if err != nil {
+		return Result{}, err
+	}
+	return Result{
+		Passphrase: passphrase,
+		Words:      words,
+		Rolled:     rolled,
+		Entropy:    o.breakdown(wordCount).Total,
+	}, nil
+}
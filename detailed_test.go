@@ -0,0 +1,37 @@
+package diceware
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerateDetailed(t *testing.T) {
+	opts := []Option{WithLanguage(LanguageMixed), WithSeparator("-"), WithNumberWord(2)}
+	res, err := GenerateDetailed(5, opts...)
+	if err != nil {
+		t.Fatalf("GenerateDetailed() error = %v", err)
+	}
+
+	if got := strings.Join(res.Words, "-"); got != res.Passphrase {
+		t.Errorf("Words joined = %q, want Passphrase %q", got, res.Passphrase)
+	}
+	if len(res.Words) != 6 || len(res.Rolled) != 6 {
+		t.Fatalf("got %d words and %d rolled words, want 6 each (5 words and the number)", len(res.Words), len(res.Rolled))
+	}
+	for i, w := range res.Rolled {
+		if w.Word != res.Words[i] {
+			t.Errorf("Rolled[%d].Word = %q, want %q", i, w.Word, res.Words[i])
+		}
+	}
+	if want := EntropyWithOptions(5, opts...); math.Abs(res.Entropy-want) > 1e-9 {
+		t.Errorf("Entropy = %f, want %f", res.Entropy, want)
+	}
+	if res.Err != nil {
+		t.Errorf("Err = %v, want nil", res.Err)
+	}
+
+	if _, err := GenerateDetailed(0); err == nil {
+		t.Error("GenerateDetailed(0) should return an error")
+	}
+}
@@ -34,15 +34,29 @@
 //	    log.Fatal(err)
 //	}
 //	fmt.Println(passphrase)
+//
+// # Concurrency
+//
+// All generation functions are safe to call from multiple goroutines at
+// once, e.g. from HTTP handlers. Each built-in wordlist is parsed once, on
+// first use, behind a sync.Once, the language registry is guarded by a lock
+// so RegisterLanguage may run alongside generation, Wordlist values are
+// immutable, and randomness comes from crypto/rand, which is itself safe
+// for concurrent use. No generation function keeps state between calls.
 package diceware
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
 	_ "embed"
+	"encoding/hex"
 	"fmt"
-	"math"
+	"io"
 	"math/big"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
 	"unicode"
 	"unicode/utf8"
 )
@@ -53,19 +67,49 @@ var wordlistEnglishData string
 //go:embed internal/wordlist/ro_diceware.txt
 var wordlistRomanianData string
 
-var wordlistEnglish map[string]string
-var wordlistRomanian map[string]string
+//go:embed internal/wordlist/reinhold_diceware.txt
+var wordlistReinholdData string
+
+//go:embed internal/wordlist/bip39_english.txt
+var wordlistBIP39EnglishData string
+
+// bip39Bits is the number of random bits indexing the BIP39 wordlist:
+// 2^11 = 2,048 words.
+const bip39Bits = 11
+
+// diceCount is the number of dice rolled per word with the standard
+// Diceware lists, including every built-in one.
+const diceCount = 5
+
+// maxDiceCount bounds the dice per word of a custom list. Six dice already
+// give 46,656 rolls, far more than any real wordlist has words.
+const maxDiceCount = 6
+
+// rollCombinations is the number of distinct five-dice rolls (6^5). Every
+// embedded wordlist must map each of them to a word.
+const rollCombinations = 7776
 
-// validWordCountEnglish and validWordCountRomanian track how many entries in
-// each wordlist actually get used to produce a word (i.e., how many survive
-// isValidWord). English entries are never filtered during generation, so its
-// count always equals len(wordlistEnglish). Romanian's raw map includes ~241
-// filler entries (digits/symbols used to fill out all 7,776 roll
-// combinations) that getWordFromLanguage rerolls past, so its usable count is
-// lower than len(wordlistRomanian). These are the counts that must be used
-// for entropy/size reporting, not the raw map length.
-var validWordCountEnglish int
-var validWordCountRomanian int
+// rollCount returns the number of distinct rolls of dice dice, 6^dice.
+func rollCount(dice int) int {
+	n := 1
+	for d := 0; d < dice; d++ {
+		n *= 6
+	}
+	return n
+}
+
+// diceForRolls is the inverse of rollCount: the number of dice whose rolls
+// index a slice of n words, or 0 if n isn't a power of 6.
+func diceForRolls(n int) int {
+	dice := 0
+	for ; n > 1 && n%6 == 0; n /= 6 {
+		dice++
+	}
+	if n != 1 {
+		return 0
+	}
+	return dice
+}
 
 // Language represents the language for passphrase generation
 type Language int
@@ -77,65 +121,248 @@ const (
 	LanguageRomanian
 	// LanguageMixed generates passphrases using a mix of English and Romanian words
 	LanguageMixed
+	// LanguageReinhold generates passphrases from Arnold Reinhold's original
+	// Diceware list, for compatibility with passphrases made with it. Its
+	// entries are lowercase and include short words, numbers and symbols
+	// (e.g. "a&p", "ph.d", "42", "@") that are all valid picks.
+	LanguageReinhold
+	// LanguageBIP39English generates passphrases from the 2,048-word BIP39
+	// English mnemonic wordlist, 11 bits per word, for cryptocurrency users
+	// used to it. Words are drawn by reading 11 random bits rather than
+	// rolling dice, so they have no dice rolls: rolls are reported as the
+	// word's index (0-2047), and PrintWordlist refuses the list.
+	//
+	// The words are independent, so a passphrase is not a BIP39 mnemonic:
+	// it has no BIP39 checksum and wallets will reject it as a seed
+	// phrase. GenerateWithChecksum's checksum word is this package's own,
+	// not BIP39's.
+	LanguageBIP39English
+
+	// numBuiltinLanguages marks the end of the built-in languages;
+	// RegisterLanguage hands out values from here on.
+	numBuiltinLanguages
 )
 
-func init() {
-	wordlistEnglish = parseWordlist(wordlistEnglishData)
-	wordlistRomanian = parseWordlist(wordlistRomanianData)
+// LanguageUnknown is reported by RolledWord for words that don't come from
+// a built-in or registered language, e.g. from an unregistered WithWordlists
+// list or WithNumberWord. It can't be used to generate passphrases.
+const LanguageUnknown Language = -1
+
+// builtinWordlists describes the embedded wordlists. Adding a built-in
+// language only takes embedding its file, adding a Language constant and
+// adding an entry here - generation, size/entropy reporting and validation
+// all look the list up in the registry rather than switching on the
+// language. Third-party languages use RegisterLanguage instead.
+var builtinWordlists = []struct {
+	lang Language
+	name string
+	data *string
+
+	// accept filters entries that can't appear in a passphrase (nil
+	// accepts everything). English entries are never filtered. Romanian
+	// includes ~241 filler entries (digits/symbols used to fill out all
+	// 7,776 roll combinations) that fail isValidWord and get rerolled, so
+	// its usable Size is lower than its raw entry count.
+	accept func(string) bool
+
+	// asciiOnly makes ValidateWordlist reject non-ASCII words.
+	asciiOnly bool
+
+	// bits marks a list of one word per line in index order, drawn by
+	// reading that many random bits instead of rolling dice.
+	bits int
+}{
+	{LanguageEnglish, "English", &wordlistEnglishData, nil, true, 0},
+	{LanguageRomanian, "Romanian", &wordlistRomanianData, isValidWord, false, 0},
+	{LanguageReinhold, "Reinhold", &wordlistReinholdData, nil, true, 0},
+	{LanguageBIP39English, "BIP39 English", &wordlistBIP39EnglishData, nil, true, bip39Bits},
+}
+
+// builtinLoads holds the built-in wordlists, parsed on first use by
+// builtinWordlist, so a program that only uses English never pays for
+// parsing the other lists. Indexed like builtinWordlists.
+var builtinLoads = make([]struct {
+	once sync.Once
+	wl   *Wordlist
+}, len(builtinWordlists))
+
+// builtinWordlist returns the built-in wordlist for lang, parsing it on the
+// first call. It is safe for concurrent use: concurrent first calls wait
+// for a single parse.
+func builtinWordlist(lang Language) (*Wordlist, bool) {
+	for i := range builtinWordlists {
+		if builtinWordlists[i].lang == lang {
+			load := &builtinLoads[i]
+			load.once.Do(func() { load.wl = parseBuiltin(i) })
+			return load.wl, true
+		}
+	}
+	return nil, false
+}
+
+// builtinSums caches WordlistChecksum, indexed like builtinWordlists.
+var builtinSums = make([]struct {
+	once sync.Once
+	sum  string
+}, len(builtinWordlists))
 
-	// English words are used as-is (no isValidWord filtering during
-	// generation), so every parsed entry is usable.
-	validWordCountEnglish = len(wordlistEnglish)
+// WordlistChecksum returns the SHA-256 of the embedded wordlist file behind
+// lang, in hex, e.g. "addd3553..." for LanguageEnglish. It identifies the
+// exact list compiled in, so a program can assert at startup that it ships
+// the list it expects, or log it to answer "which list version are you
+// on?". The hash covers the file's bytes, not the parsed words, so it
+// matches sha256sum of the file in the source tree. It is computed once,
+// on first use.
+//
+// Returns "" for LanguageMixed, which has no single file, and for
+// registered and unsupported languages.
+func WordlistChecksum(lang Language) string {
+	for i := range builtinWordlists {
+		if builtinWordlists[i].lang == lang {
+			s := &builtinSums[i]
+			s.once.Do(func() {
+				sum := sha256.Sum256([]byte(*builtinWordlists[i].data))
+				s.sum = hex.EncodeToString(sum[:])
+			})
+			return s.sum
+		}
+	}
+	return ""
+}
 
-	// Romanian entries that fail isValidWord get rerolled at generation
-	// time and can never appear in output, so only count the ones that
-	// would actually be selectable.
-	for _, word := range wordlistRomanian {
-		if isValidWord(word) {
-			validWordCountRomanian++
+// parseBuiltin parses the embedded data of builtinWordlists[i]. An empty
+// embed means the build went wrong, e.g. a wordlist file truncated or
+// missing when it was embedded; it panics saying so rather than leaving
+// generation to fail later without a word to pick.
+func parseBuiltin(i int) *Wordlist {
+	b := builtinWordlists[i]
+	if strings.TrimSpace(*b.data) == "" {
+		panic(fmt.Sprintf("the embedded %s wordlist is empty; the build is broken", b.name))
+	}
+	var wl *Wordlist
+	if b.bits > 0 {
+		wl = newWordlist(b.name, parseIndexedWordlist(*b.data, b.bits), b.accept)
+		wl.bits = b.bits
+	} else {
+		wl = newWordlist(b.name, parseWordlist(*b.data), b.accept)
+	}
+	wl.lang = b.lang
+	wl.asciiOnly = b.asciiOnly
+	return wl
+}
+
+// parseIndexedWordlist parses an embedded list of one word per line, in
+// index order, into a slice of 2^bits words. Blank lines and lines starting
+// with '#' are skipped. Like parseWordlist it panics on a malformed embed,
+// here one with the wrong number of words.
+func parseIndexedWordlist(data string, bits int) []string {
+	words := make([]string, 0, 1<<bits)
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
 		}
+		words = append(words, toNFC(line))
+	}
+	if len(words) != 1<<bits {
+		panic(fmt.Sprintf("indexed wordlist has %d words, want %d", len(words), 1<<bits))
+	}
+	return words
+}
+
+// parseWordlist parses an embedded wordlist file into a roll-indexed slice
+// (see readWordlist). The embeds are part of the build, so a malformed one
+// is a programming error: it panics rather than returning the error from
+// readWordlist, as it does for one without any entries.
+func parseWordlist(data string) []string {
+	words, n, _, err := readWordlist(data, true)
+	if err != nil {
+		panic(err.Error())
 	}
+	if n == 0 {
+		panic("embedded wordlist has no entries")
+	}
+	return words
 }
 
-// parseWordlist parses the embedded wordlist file into a map
-// It validates the format and panics if the wordlist is malformed
-func parseWordlist(data string) map[string]string {
-	result := make(map[string]string, 7776) // Pre-allocate for expected size
+// readWordlist parses wordlist data in the "<roll> <word>" line format into
+// a slice of rollCount(dice) words indexed by rollToIndex, with "" for
+// rolls the data has no entry for, and returns it along with the number of
+// entries read. The number of dice is that of the first roll, e.g. 4 for
+// EFF's short lists, and every other roll must have as many. Blank lines
+// and lines starting with '#' are skipped, and everything after the roll is
+// the word, so multi-word entries like "11111 ice cream" keep their
+// (single) spaces. Files saved on Windows read the same as Unix ones:
+// "\r\n" line endings, a leading UTF-8 byte order mark and whitespace
+// around the word are all dropped. Words are normalized to NFC (see
+// toNFC), so they have the same bytes whichever form the file used.
+//
+// In strict mode the first malformed line (missing word, invalid or
+// duplicate roll) is reported as an error with its line number; otherwise
+// such lines are skipped, keeping the first entry for a duplicated roll,
+// and their errors returned as skipped.
+func readWordlist(data string, strict bool) (words []string, n int, skipped []error, err error) {
+	data = strings.TrimPrefix(data, byteOrderMark)
 	lines := strings.Split(data, "\n")
+	dice := detectDiceCount(lines)
+	words = make([]string, rollCount(dice))
 
 	for i, line := range lines {
+		line = strings.TrimSuffix(line, "\r")
 		line = strings.TrimSpace(line)
-		if line == "" {
+		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
 
+		var lineErr error
 		parts := strings.Fields(line)
-		if len(parts) != 2 {
-			panic(fmt.Sprintf("invalid wordlist format at line %d: expected 2 fields, got %d: %q", i+1, len(parts), line))
-		}
-
 		roll := parts[0]
-		word := parts[1]
-
-		// Validate roll format (5 digits, each 1-6)
-		if !isValidRoll(roll) {
-			panic(fmt.Sprintf("invalid dice roll at line %d: %q (expected 5 digits between 1-6)", i+1, roll))
+		index, valid := rollToIndex(roll, dice)
+		switch {
+		case len(parts) < 2:
+			lineErr = fmt.Errorf("invalid wordlist format at line %d: expected a dice roll and a word: %q", i+1, line)
+		case !valid:
+			lineErr = fmt.Errorf("invalid dice roll at line %d: %q (expected %d digits between 1-6)", i+1, roll, dice)
+		case words[index] != "":
+			lineErr = fmt.Errorf("duplicate dice roll at line %d: %q", i+1, roll)
 		}
-
-		// Check for duplicate rolls
-		if _, exists := result[roll]; exists {
-			panic(fmt.Sprintf("duplicate dice roll at line %d: %q", i+1, roll))
+		if lineErr != nil {
+			if strict {
+				return nil, 0, nil, lineErr
+			}
+			skipped = append(skipped, lineErr)
+			continue
 		}
 
-		result[roll] = word
+		words[index] = toNFC(strings.Join(parts[1:], " "))
+		n++
 	}
 
-	return result
+	return words, n, skipped, nil
 }
 
-// isValidRoll checks if a roll string is valid (5 digits, each 1-6)
-func isValidRoll(roll string) bool {
-	if len(roll) != 5 {
+// byteOrderMark is the UTF-8 encoded BOM some Windows editors put at the
+// start of text files.
+const byteOrderMark = "\ufeff"
+
+// detectDiceCount returns the number of dice of the first line of a
+// wordlist that starts with a plausible roll, or diceCount if none does.
+func detectDiceCount(lines []string) int {
+	for _, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if n := len(fields[0]); n <= maxDiceCount && isValidRoll(fields[0], n) {
+			return n
+		}
+	}
+	return diceCount
+}
+
+// isValidRoll checks if a roll string is valid (dice digits, each 1-6)
+func isValidRoll(roll string, dice int) bool {
+	if dice < 1 || len(roll) != dice {
 		return false
 	}
 	for _, ch := range roll {
@@ -146,26 +373,244 @@ func isValidRoll(roll string) bool {
 	return true
 }
 
-// rollDice simulates rolling a single die (1-6) using cryptographically secure random numbers
-func rollDice() (int, error) {
-	n, err := rand.Int(rand.Reader, big.NewInt(6))
+// rollToIndex is the inverse of indexToRoll: it converts a roll of dice
+// dice into its 0-based index, reporting false for anything that isn't
+// dice digits between 1-6.
+func rollToIndex(roll string, dice int) (int, bool) {
+	if !isValidRoll(roll, dice) {
+		return 0, false
+	}
+	i := 0
+	for pos := 0; pos < len(roll); pos++ {
+		i = i*6 + int(roll[pos]-'1')
+	}
+	return i, true
+}
+
+// indexToRoll converts a 0-based index in [0, rollCount(dice)) into its
+// roll string, treating the roll as a base-6 number with dice digits
+// shifted to 1-6 (for five dice, 0 -> "11111" and 7775 -> "66666").
+func indexToRoll(i, dice int) string {
+	var buf [maxDiceCount]byte
+	roll := buf[:dice]
+	for pos := len(roll) - 1; pos >= 0; pos-- {
+		roll[pos] = byte('1' + i%6)
+		i /= 6
+	}
+	return string(roll)
+}
+
+// RollToIndex converts a five-dice roll like "11111" into its 0-based
+// index in a wordlist, 0 to 7775, treating the roll as a base-6 number
+// with digits 1-6: "11111" is 0, "11112" is 1 and "66666" is 7775. It is
+// the inverse of IndexToRoll, e.g. for storing rolls compactly or for
+// interop with index-based lists. Returns an error for anything but five
+// digits between 1-6.
+func RollToIndex(roll string) (int, error) {
+	i, ok := rollToIndex(roll, diceCount)
+	if !ok {
+		return 0, fmt.Errorf("invalid dice roll %q (expected %d digits 1-6)", roll, diceCount)
+	}
+	return i, nil
+}
+
+// IndexToRoll converts an index from 0 to 7775 into its five-dice roll,
+// the inverse of RollToIndex: 0 is "11111" and 7775 is "66666". It also
+// maps external randomness onto rolls: a number drawn uniformly from
+// [0, 7776) gives a uniformly random roll. Returns an error for an index
+// out of range.
+func IndexToRoll(i int) (string, error) {
+	if i < 0 || i >= rollCombinations {
+		return "", fmt.Errorf("roll index must be between 0 and %d, got %d", rollCombinations-1, i)
+	}
+	return indexToRoll(i, diceCount), nil
+}
+
+// ValidateWordlist checks that the embedded wordlist for the specified
+// language is complete and well-formed: every one of the 7,776 five-dice
+// rolls must map to a word, no word may appear more than once, and English
+// words must be non-empty ASCII. LanguageMixed validates both underlying
+// wordlists. A registered language may cover only some of its rolls (see
+// RegisterLanguage), of any size, so for those only the words present are
+// checked.
+//
+// The wordlists are parsed once, on first use, so a corrupt or truncated
+// embed would otherwise only show up as a "no word found for dice roll"
+// error at some random point during generation. Call this at startup to
+// fail fast instead; it also takes the parsing cost up front.
+func ValidateWordlist(lang Language) error {
+	if lang == LanguageMixed {
+		if err := ValidateWordlist(LanguageEnglish); err != nil {
+			return err
+		}
+		return ValidateWordlist(LanguageRomanian)
+	}
+
+	wl, ok := lookupWordlist(lang)
+	if !ok {
+		return unsupportedLanguage(lang)
+	}
+	switch {
+	case wl.bits > 0:
+		return checkWords(wl.name, wl.words, wl.asciiOnly, false, strconv.Itoa)
+	case lang >= numBuiltinLanguages:
+		return checkWords(wl.name, wl.words, wl.asciiOnly, true, func(i int) string {
+			return indexToRoll(i, wl.dice)
+		})
+	}
+	return validateWordlist(wl.name, wl.words, wl.asciiOnly)
+}
+
+// validateWordlist does the actual checks behind ValidateWordlist for a
+// single parsed wordlist. name is only used in error messages.
+func validateWordlist(name string, words []string, requireASCII bool) error {
+	dice := diceForRolls(len(words))
+	if dice == 0 {
+		return fmt.Errorf("%s wordlist has %d rolls, want a power of 6 (e.g. %d for %d dice)", name, len(words), rollCombinations, diceCount)
+	}
+	return checkWords(name, words, requireASCII, false, func(i int) string {
+		return indexToRoll(i, dice)
+	})
+}
+
+// checkWords checks that every entry of words is present, unless sparse is
+// set, and that they are distinct, and ASCII if requireASCII is set,
+// naming entry i by rollOf(i) in errors.
+func checkWords(name string, words []string, requireASCII, sparse bool, rollOf func(i int) string) error {
+	seen := make(map[string]string, len(words))
+	for i, word := range words {
+		if word == "" && sparse {
+			continue
+		}
+		roll := rollOf(i)
+		if word == "" {
+			return fmt.Errorf("%s wordlist is missing dice roll %s", name, roll)
+		}
+		if prev, dup := seen[word]; dup {
+			return fmt.Errorf("%s wordlist has duplicate word %q (dice rolls %s and %s)", name, word, prev, roll)
+		}
+		seen[word] = roll
+
+		if requireASCII && !isASCII(word) {
+			return fmt.Errorf("%s wordlist has non-ASCII word %q for dice roll %s", name, word, roll)
+		}
+	}
+
+	return nil
+}
+
+// dieRejectAbove is the largest multiple of 6 a byte can hold (42 × 6).
+// rollDice rejects bytes from here up so every face is equally likely.
+const dieRejectAbove = 252
+
+// rollDice simulates rolling a single die (1-6) using random numbers from r,
+// which is crypto/rand.Reader unless WithRandReader or a seeded Generator
+// swaps it out. Each die takes one byte of the stream; the 4 byte values
+// that would favor some faces are rejected and the next byte is used, so
+// on average a die costs about 1.016 bytes.
+func rollDice(r io.Reader) (int, error) {
+	for {
+		b, err := readByte(r)
+		if err != nil {
+			return 0, randomSourceError(err)
+		}
+		if b < dieRejectAbove {
+			return int(b%6) + 1, nil
+		}
+	}
+}
+
+// readByte reads a single byte from r, without allocating if r is an
+// io.ByteReader like the batchReader generation uses.
+func readByte(r io.Reader) (byte, error) {
+	if br, ok := r.(io.ByteReader); ok {
+		return br.ReadByte()
+	}
+	var b [1]byte
+	_, err := io.ReadFull(r, b[:])
+	return b[0], err
+}
+
+// randomUnitFloat returns a uniformly distributed float64 in [0, 1) with 53
+// bits of precision, using random numbers from r
+func randomUnitFloat(r io.Reader) (float64, error) {
+	n, err := rand.Int(r, big.NewInt(1<<53))
+	if err != nil {
+		return 0, randomSourceError(err)
+	}
+	return float64(n.Int64()) / (1 << 53), nil
+}
+
+// rollFiveDice rolls five dice using random numbers from r and returns the
+// result as a string (e.g., "11111"). It is rollNDice for the standard
+// lists.
+func rollFiveDice(r io.Reader) (string, error) {
+	i, err := rollNDice(r, diceCount)
 	if err != nil {
-		return 0, fmt.Errorf("failed to generate random number: %w", err)
+		return "", err
+	}
+	return indexToRoll(i, diceCount), nil
+}
+
+// readBits reads n (at most 16) uniformly random bits from r and returns
+// them as an index in [0, 2^n), for lists indexed by bits rather than
+// dice: every value of the bytes read maps to the same number of indexes,
+// so no rejection is needed.
+func readBits(r io.Reader, n int) (int, error) {
+	v := 0
+	for read := 0; read < n; read += 8 {
+		b, err := readByte(r)
+		if err != nil {
+			return 0, randomSourceError(err)
+		}
+		v = v<<8 | int(b)
 	}
-	return int(n.Int64()) + 1, nil
+	return v & (1<<n - 1), nil
 }
 
-// rollFiveDice rolls five dice and returns the result as a string (e.g., "11111")
-func rollFiveDice() (string, error) {
-	var result strings.Builder
-	for i := 0; i < 5; i++ {
-		roll, err := rollDice()
+// rollNDice rolls n dice using random numbers from r and returns the
+// 0-based index of the roll (see indexToRoll), each die contributing one
+// base-6 digit. This is what generation uses to index Wordlist.words; the
+// roll string is only built for the words that are kept.
+func rollNDice(r io.Reader, n int) (int, error) {
+	i := 0
+	for d := 0; d < n; d++ {
+		roll, err := rollDice(r)
 		if err != nil {
-			return "", err
+			return 0, err
+		}
+		i = i*6 + roll - 1
+	}
+	return i, nil
+}
+
+// rollUniform draws an index in [0, n) using random numbers from r in a
+// single draw rather than die by die: it reads the fewest bytes that can
+// hold n values (two for the 7,776 rolls of five dice) as a big-endian
+// number, rejecting the top values that would favor some indexes and
+// drawing again, like rollDice does for a single die. For five dice that
+// rejects 5% of draws, so a roll costs about 2.1 bytes instead of 5.1,
+// and no die of it depends on a byte another die used.
+func rollUniform(r io.Reader, n int) (int, error) {
+	size := 1
+	for 1<<(8*size) < n {
+		size++
+	}
+	space := uint64(1) << (8 * size)
+	limit := space - space%uint64(n)
+	for {
+		var v uint64
+		for j := 0; j < size; j++ {
+			b, err := readByte(r)
+			if err != nil {
+				return 0, randomSourceError(err)
+			}
+			v = v<<8 | uint64(b)
+		}
+		if v < limit {
+			return int(v % uint64(n)), nil
 		}
-		result.WriteString(fmt.Sprintf("%d", roll))
 	}
-	return result.String(), nil
 }
 
 // getWord rolls five dice and returns the corresponding word from the wordlist,
@@ -188,67 +633,44 @@ func isValidWord(word string) bool {
 }
 
 // rollWord rolls five dice and resolves them to a word for the specified
-// language, rerolling internally (up to maxAttempts) if the roll lands on a
-// filtered/invalid entry - e.g. Romanian's ~241 numeric/symbol filler
-// entries. Returns the raw (uncapitalized) word alongside the winning dice
-// roll string. This is the single place the reroll/language-selection logic
-// lives; getWordFromLanguage and GenerateWithRollsAndLanguage both build on
-// top of it instead of duplicating the switch/reroll logic.
-func rollWord(lang Language) (word string, roll string, err error) {
-	const maxAttempts = 100 // Prevent infinite loops
-
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		roll, err = rollFiveDice()
-		if err != nil {
-			return "", "", err
-		}
-
-		var exists bool
-
-		switch lang {
-		case LanguageEnglish:
-			word, exists = wordlistEnglish[roll]
-		case LanguageRomanian:
-			word, exists = wordlistRomanian[roll]
-			// Romanian wordlist contains some non-word entries (numbers, symbols)
-			// Re-roll if we get one of those
-			if exists && !isValidWord(word) {
-				continue
-			}
-		case LanguageMixed:
-			// For mixed mode, randomly choose between English and Romanian
-			useBool, berr := rand.Int(rand.Reader, big.NewInt(2))
-			if berr != nil {
-				return "", "", fmt.Errorf("failed to select language: %w", berr)
-			}
-			if useBool.Int64() == 0 {
-				word, exists = wordlistEnglish[roll]
-			} else {
-				word, exists = wordlistRomanian[roll]
-				// Re-roll if we get a non-word from Romanian wordlist
-				if exists && !isValidWord(word) {
-					continue
-				}
-			}
-		default:
-			return "", "", fmt.Errorf("unsupported language: %v", lang)
-		}
-
-		if !exists {
-			return "", "", fmt.Errorf("no word found for dice roll: %s", roll)
-		}
+// language, rerolling internally if the roll lands on a filtered/invalid
+// entry - e.g. Romanian's ~241 numeric/symbol filler entries. Returns the
+// raw (uncapitalized) word alongside the winning dice roll string. The
+// actual reroll logic lives in drawWord; this only maps the language to its
+// wordlist(s).
+//
+// englishRatio is only used for LanguageMixed: it is the probability that
+// an attempt draws from the English wordlist rather than the Romanian one.
+func rollWord(lang Language, englishRatio float64) (word string, roll string, err error) {
+	lists, weights, err := languageWordlists(lang, englishRatio)
+	if err != nil {
+		return "", "", err
+	}
+	word, roll, _, err = drawWord(rand.Reader, lists, weights)
+	return word, roll, err
+}
 
-		return word, roll, nil
+// languageWordlists returns the wordlist(s) and selection weights (nil for a
+// single list) backing the specified language.
+func languageWordlists(lang Language, englishRatio float64) ([]*Wordlist, []float64, error) {
+	if lang == LanguageMixed {
+		en, _ := lookupWordlist(LanguageEnglish)
+		ro, _ := lookupWordlist(LanguageRomanian)
+		return []*Wordlist{en, ro}, []float64{englishRatio, 1 - englishRatio}, nil
 	}
 
-	return "", "", fmt.Errorf("failed to generate valid word after %d attempts", maxAttempts)
+	wl, ok := lookupWordlist(lang)
+	if !ok {
+		return nil, nil, unsupportedLanguage(lang)
+	}
+	return []*Wordlist{wl}, nil, nil
 }
 
 // getWordFromLanguage rolls five dice and returns the corresponding word from the specified language wordlist,
 // capitalized to match the Diceware web implementation. For Romanian, it re-rolls if it gets a non-alphabetic
 // entry (numbers, symbols, etc.) - see rollWord.
 func getWordFromLanguage(lang Language) (string, error) {
-	word, _, err := rollWord(lang)
+	word, _, err := rollWord(lang, defaultMixedRatio)
 	if err != nil {
 		return "", err
 	}
@@ -261,6 +683,10 @@ func getWordFromLanguage(lang Language) (string, error) {
 // characters (e.g. accented letters) are capitalized correctly instead of
 // being corrupted. Currently a no-op concern for the shipped wordlists (no
 // surviving entry starts with a multi-byte rune), but wordlists change.
+//
+// It uses title case rather than upper case, which differs for digraphs
+// like "ǆ" (title "ǅ", upper "Ǆ"). Locale-specific rules such as Turkish
+// dotted İ are out of scope; see WithCapitalizer.
 func capitalize(word string) string {
 	if word == "" {
 		return word
@@ -271,7 +697,18 @@ func capitalize(word string) string {
 		// rather than risk further corruption.
 		return word
 	}
-	return string(unicode.ToUpper(r)) + word[size:]
+	return string(unicode.ToTitle(r)) + word[size:]
+}
+
+// appendCapitalized appends word to buf with its first letter capitalized,
+// like capitalize but without allocating a new string.
+func appendCapitalized(buf []byte, word string) []byte {
+	r, size := utf8.DecodeRuneInString(word)
+	if word == "" || (r == utf8.RuneError && size <= 1) {
+		return append(buf, word...)
+	}
+	buf = utf8.AppendRune(buf, unicode.ToTitle(r))
+	return append(buf, word[size:]...)
 }
 
 // Generate creates a passphrase with the specified number of words.
@@ -294,6 +731,22 @@ func Generate(wordCount int) (string, error) {
 	return GenerateWithSeparator(wordCount, "")
 }
 
+// MustGenerate is like Generate but panics if generation fails, for
+// package-level variables, examples and tests where a failing crypto/rand
+// is fatal anyway:
+//
+//	var demoPassphrase = diceware.MustGenerate(6)
+//
+// It panics if wordCount is less than 1 or if random number generation
+// fails. Use Generate wherever an error can be handled.
+func MustGenerate(wordCount int) string {
+	passphrase, err := Generate(wordCount)
+	if err != nil {
+		panic("diceware: " + err.Error())
+	}
+	return passphrase
+}
+
 // GenerateWithSeparator creates a passphrase with the specified number of words
 // and joins them with the provided separator. Words are capitalized.
 //
@@ -325,20 +778,150 @@ func GenerateWithLanguage(wordCount int, lang Language) (string, error) {
 //
 // Returns an error if wordCount is less than 1 or if random number generation fails.
 func GenerateWithLanguageAndSeparator(wordCount int, lang Language, separator string) (string, error) {
+	return GenerateWithOptions(wordCount, WithLanguage(lang), WithSeparator(separator))
+}
+
+// GenerateWords returns the chosen words themselves (capitalized) rather
+// than a joined passphrase, so callers can join, store, or display them
+// however they like without splitting a string back apart - which is
+// impossible in general once the separator is empty.
+//
+// Returns an error if wordCount is less than 1 or if random number generation fails.
+func GenerateWords(wordCount int, lang Language) ([]string, error) {
+	_, words, _, err := generate(wordCount, newOptions(WithLanguage(lang)))
+	if err != nil {
+		return nil, err
+	}
+	return words, nil
+}
+
+// GenerateByLength generates a passphrase of about targetChars characters
+// rather than a fixed number of words, e.g. to fit a field of a given
+// visual width: it keeps adding capitalized words, with no separator, and
+// stops at the first word that makes the passphrase at least targetChars
+// long, so it overshoots by less than one word. It returns the passphrase
+// along with its entropy, from the number of words it took.
+//
+// Long words mean fewer words, so the entropy varies from one passphrase
+// to the next; check it, or use a word count, when a minimum matters. With
+// English words of 3 to 9 letters, a target of 30 takes 4 to 10 words.
+//
+// Returns an error if targetChars is less than 1, if the language is
+// unsupported, or if random number generation fails.
+func GenerateByLength(targetChars int, lang Language) (passphrase string, entropy float64, err error) {
+	if targetChars < 1 {
+		return "", 0, fmt.Errorf("target length must be at least 1, got %d", targetChars)
+	}
+	lists, weights, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return "", 0, err
+	}
+
+	br := newBatchReader(rand.Reader, targetChars/3+1)
+	defer br.wipe()
+
+	var b strings.Builder
+	length, wordCount := 0, 0
+	for length < targetChars {
+		word, _, _, err := drawWord(br, lists, weights)
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to generate word %d: %w", wordCount+1, err)
+		}
+		b.WriteString(capitalize(word))
+		length += utf8.RuneCountInString(word)
+		wordCount++
+	}
+	return b.String(), EntropyForLanguage(wordCount, lang), nil
+}
+
+// GenerateTo writes a passphrase with the specified number of words in the
+// specified language(s), joined with separator, directly to w - e.g. an
+// http.ResponseWriter or bytes.Buffer. It skips the intermediate word slice
+// and string the other Generate functions build, assembling the output in
+// a single buffer that is handed to w in one Write call, which matters for
+// services minting many passphrases.
+//
+// Nothing is written if generation fails. Returns an error if wordCount is
+// less than 1, if the language is unsupported, if random number generation
+// fails, or if the write fails.
+func GenerateTo(w io.Writer, wordCount int, lang Language, separator string) error {
+	buf, err := appendPassphrase(nil, wordCount, lang, separator)
+	if err != nil {
+		return err
+	}
+	defer Wipe(buf)
+	_, err = w.Write(buf)
+	return err
+}
+
+// GenerateBytes is like Generate but returns the passphrase as a byte slice
+// that the caller can erase with Wipe once done with it, which is
+// impossible with an immutable string:
+//
+//	passphrase, err := diceware.GenerateBytes(6)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer diceware.Wipe(passphrase)
+//
+// The passphrase is assembled directly in the returned slice, so no string
+// copies of it are made. The words themselves come from the wordlists,
+// which are public anyway; the dice rolls that selected them are
+// short-lived strings that can't be wiped.
+//
+// Returns an error if wordCount is less than 1 or if random number
+// generation fails.
+func GenerateBytes(wordCount int) ([]byte, error) {
+	return appendPassphrase(nil, wordCount, LanguageEnglish, "")
+}
+
+// Wipe overwrites b with zeros, e.g. to erase a passphrase from
+// GenerateBytes from memory as soon as it is no longer needed.
+func Wipe(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+	runtime.KeepAlive(b)
+}
+
+// appendPassphrase appends a passphrase of wordCount capitalized words in
+// lang, joined with separator, to buf, growing it at most once. This is the
+// allocation-light path behind GenerateTo and GenerateBytes.
+func appendPassphrase(buf []byte, wordCount int, lang Language, separator string) ([]byte, error) {
 	if wordCount < 1 {
-		return "", fmt.Errorf("word count must be at least 1, got %d", wordCount)
+		return nil, invalidWordCount(wordCount)
 	}
+	lists, weights, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	br := newBatchReader(rand.Reader, wordCount)
+	defer br.wipe()
 
-	words := make([]string, wordCount)
-	for i := 0; i < wordCount; i++ {
-		word, err := getWordFromLanguage(lang)
+	raw := make([]string, wordCount)
+	size := len(separator) * (wordCount - 1)
+	for i := range raw {
+		word, _, _, err := drawWord(br, lists, weights)
 		if err != nil {
-			return "", fmt.Errorf("failed to generate word %d: %w", i+1, err)
+			return nil, fmt.Errorf("failed to generate word %d: %w", i+1, err)
 		}
-		words[i] = word
+		raw[i] = word
+		size += len(word) + utf8.UTFMax // room for a wider capital
 	}
 
-	return strings.Join(words, separator), nil
+	if cap(buf)-len(buf) < size {
+		grown := make([]byte, len(buf), len(buf)+size)
+		copy(grown, buf)
+		buf = grown
+	}
+	for i, word := range raw {
+		if i > 0 {
+			buf = append(buf, separator...)
+		}
+		buf = appendCapitalized(buf, word)
+	}
+	return buf, nil
 }
 
 // GenerateWithRolls returns both the passphrase and the dice rolls used to generate it.
@@ -351,7 +934,8 @@ func GenerateWithRolls(wordCount int) (passphrase string, rolls []string, err er
 }
 
 // GenerateWithRollsAndLanguage returns both the passphrase and the dice rolls used to generate it
-// using the specified language(s). Words are capitalized and concatenated with no separator.
+// using the specified language(s). Words are capitalized and concatenated with no separator;
+// GenerateWithRollsLanguageAndSeparator joins them with one instead.
 //
 // Returns a passphrase, a slice of dice roll strings, and an error.
 func GenerateWithRollsAndLanguage(wordCount int, lang Language) (passphrase string, rolls []string, err error) {
@@ -367,23 +951,53 @@ func GenerateWithRollsAndLanguage(wordCount int, lang Language) (passphrase stri
 //
 // Returns a passphrase, a slice of dice roll strings, and an error.
 func GenerateWithRollsLanguageAndSeparator(wordCount int, lang Language, separator string) (passphrase string, rolls []string, err error) {
-	if wordCount < 1 {
-		return "", nil, fmt.Errorf("word count must be at least 1, got %d", wordCount)
+	return GenerateWithRollsAndOptions(wordCount, WithLanguage(lang), WithSeparator(separator))
+}
+
+// GenerateWithRollsAndOptions returns both the passphrase and the dice rolls
+// used to generate it, configured by opts like GenerateWithOptions. Extra
+// elements that weren't rolled, like a WithNumberWord number, have an empty
+// roll.
+//
+// Returns a passphrase, a slice of dice roll strings, and an error.
+func GenerateWithRollsAndOptions(wordCount int, opts ...Option) (passphrase string, rolls []string, err error) {
+	passphrase, _, rolled, err := generate(wordCount, newOptions(opts...))
+	if err != nil {
+		return "", nil, err
 	}
+	rolls = make([]string, len(rolled))
+	for i, r := range rolled {
+		rolls[i] = r.Roll
+	}
+	return passphrase, rolls, nil
+}
 
-	words := make([]string, wordCount)
-	rolls = make([]string, wordCount)
+// GenerateRollsOnly returns wordCount random five-dice rolls, e.g.
+// "43434", without looking up any words. It separates the secret step,
+// randomness, from the public one, the wordlist: generate the rolls here
+// and look them up on another machine, say an air-gapped one, in a
+// wordlist audited on its own (see WordAt, or PrintWordlist for a printed
+// copy). Every roll is equally likely, so they suit any five-dice list,
+// but a roll the list has no usable word for should be rolled again.
+//
+// Returns an error if wordCount is less than 1 or if random number
+// generation fails.
+func GenerateRollsOnly(wordCount int) ([]string, error) {
+	if wordCount < 1 {
+		return nil, invalidWordCount(wordCount)
+	}
+	br := newBatchReader(rand.Reader, wordCount)
+	defer br.wipe()
 
-	for i := 0; i < wordCount; i++ {
-		word, roll, werr := rollWord(lang)
-		if werr != nil {
-			return "", nil, fmt.Errorf("failed to generate word %d: %w", i+1, werr)
+	rolls := make([]string, wordCount)
+	for i := range rolls {
+		idx, err := rollUniform(br, rollCombinations)
+		if err != nil {
+			return nil, err
 		}
-		words[i] = capitalize(word)
-		rolls[i] = roll
+		rolls[i] = indexToRoll(idx, diceCount)
 	}
-
-	return strings.Join(words, separator), rolls, nil
+	return rolls, nil
 }
 
 // Entropy calculates the bits of entropy for a given number of words,
@@ -391,7 +1005,8 @@ func GenerateWithRollsLanguageAndSeparator(wordCount int, lang Language, separat
 // EntropyForLanguage(wordCount, LanguageEnglish).
 //
 // The EFF large wordlist has 7,776 usable words (6^5), providing ~12.925
-// bits per word.
+// bits per word; the figure is computed from the list's size (see
+// EntropyForBitsPerWord), not hardcoded.
 func Entropy(wordCount int) float64 {
 	return EntropyForLanguage(wordCount, LanguageEnglish)
 }
@@ -407,16 +1022,24 @@ func Entropy(wordCount int) float64 {
 //     ~13.902 bits/word, since each word also carries the extra bit from
 //     the English/Romanian coin flip
 func EntropyForLanguage(wordCount int, lang Language) float64 {
-	size := WordlistSizeByLanguage(lang)
-	if size < 2 {
-		return 0
-	}
-	return float64(wordCount) * math.Log2(float64(size))
+	return EntropyForBitsPerWord(wordCount, bitsForSize(WordlistSizeByLanguage(lang)))
+}
+
+// EntropyForBitsPerWord returns the bits of entropy of wordCount words that
+// each add bitsPerWord bits, i.e. their product. EntropyForLanguage uses it
+// with log2 of the language's usable size; call it directly for a list
+// described only by its bits, e.g. WordlistInfo.BitsPerWord, or to test
+// code that depends on entropy with round figures:
+//
+//	diceware.EntropyForBitsPerWord(6, 11) // 66, six BIP39 words
+func EntropyForBitsPerWord(wordCount int, bitsPerWord float64) float64 {
+	return float64(wordCount) * bitsPerWord
 }
 
 // WordlistSize returns the number of usable words in the English wordlist
 func WordlistSize() int {
-	return validWordCountEnglish
+	wl, _ := lookupWordlist(LanguageEnglish)
+	return wl.Size()
 }
 
 // WordlistSizeByLanguage returns the number of usable words in the wordlist
@@ -424,19 +1047,17 @@ func WordlistSize() int {
 // actually produce a word during generation (not the raw entry count -
 // Romanian's raw wordlist includes ~241 filler entries that are skipped).
 func WordlistSizeByLanguage(lang Language) int {
-	switch lang {
-	case LanguageEnglish:
-		return validWordCountEnglish
-	case LanguageRomanian:
-		return validWordCountRomanian
-	case LanguageMixed:
+	if lang == LanguageMixed {
 		// Mixed mode selects with a fair coin flip between the two
 		// wordlists and rerolls the whole attempt (coin + dice) if it
 		// lands on an invalid Romanian entry. That rejection sampling
 		// preserves uniformity, so the combined usable space really is
 		// just the sum of both usable counts.
-		return validWordCountEnglish + validWordCountRomanian
-	default:
-		return 0
+		return WordlistSizeByLanguage(LanguageEnglish) + WordlistSizeByLanguage(LanguageRomanian)
+	}
+
+	if wl, ok := lookupWordlist(lang); ok {
+		return wl.Size()
 	}
+	return 0
 }
@@ -1,7 +1,12 @@
 package diceware
 
 import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"strings"
+	"sync"
 	"testing"
 	"unicode/utf8"
 )
@@ -126,7 +131,7 @@ func TestGenerateWithRolls(t *testing.T) {
 func TestRollDice(t *testing.T) {
 	// Test that rollDice returns values between 1 and 6
 	for i := 0; i < 100; i++ {
-		result, err := rollDice()
+		result, err := rollDice(rand.Reader)
 		if err != nil {
 			t.Fatalf("rollDice() failed: %v", err)
 		}
@@ -139,7 +144,7 @@ func TestRollDice(t *testing.T) {
 func TestRollFiveDice(t *testing.T) {
 	// Test that rollFiveDice returns a valid 5-digit string
 	for i := 0; i < 100; i++ {
-		result, err := rollFiveDice()
+		result, err := rollFiveDice(rand.Reader)
 		if err != nil {
 			t.Fatalf("rollFiveDice() failed: %v", err)
 		}
@@ -190,6 +195,25 @@ func TestEntropy(t *testing.T) {
 	}
 }
 
+func TestEntropyForBitsPerWord(t *testing.T) {
+	if got := EntropyForBitsPerWord(6, 11); got != 66 {
+		t.Errorf("EntropyForBitsPerWord(6, 11) = %f, want 66", got)
+	}
+	if got := EntropyForBitsPerWord(0, 12.5); got != 0 {
+		t.Errorf("EntropyForBitsPerWord(0, 12.5) = %f, want 0", got)
+	}
+	// Entropy is the same computation with the list's own bits
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageBIP39English} {
+		info, err := WordlistInfoByLanguage(lang)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := EntropyForLanguage(7, lang), EntropyForBitsPerWord(7, info.BitsPerWord); got != want {
+			t.Errorf("EntropyForLanguage(7, %v) = %f, want %f", lang, got, want)
+		}
+	}
+}
+
 func TestEntropyForLanguage(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -246,8 +270,8 @@ func TestParseWordlist(t *testing.T) {
 
 	result := parseWordlist(testData)
 
-	if len(result) != 3 {
-		t.Errorf("parseWordlist() returned %d entries, want 3", len(result))
+	if n := entryCount(result); n != 3 {
+		t.Errorf("parseWordlist() returned %d entries, want 3", n)
 	}
 
 	tests := []struct {
@@ -260,7 +284,8 @@ func TestParseWordlist(t *testing.T) {
 	}
 
 	for _, tt := range tests {
-		if got := result[tt.roll]; got != tt.want {
+		i, _ := rollToIndex(tt.roll, diceCount)
+		if got := result[i]; got != tt.want {
 			t.Errorf("parseWordlist()[%s] = %s, want %s", tt.roll, got, tt.want)
 		}
 	}
@@ -275,12 +300,92 @@ func TestParseWordlistEmptyLines(t *testing.T) {
 
 	result := parseWordlist(testData)
 
-	if len(result) != 3 {
-		t.Errorf("parseWordlist() with empty lines returned %d entries, want 3", len(result))
+	if n := entryCount(result); n != 3 {
+		t.Errorf("parseWordlist() with empty lines returned %d entries, want 3", n)
+	}
+}
+
+func TestParseWordlistWindows(t *testing.T) {
+	unix := parseWordlist("11111\tabacus\n11112\tice cream\n11113\tabdominal\n")
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"CRLF", "11111\tabacus\r\n11112\tice cream\r\n11113\tabdominal\r\n"},
+		{"CRLF with trailing whitespace", "11111\tabacus \r\n11112\tice cream\t \r\n\r\n11113\tabdominal  \r\n"},
+		{"BOM and CRLF", "\ufeff11111\tabacus\r\n11112\tice cream\r\n11113\tabdominal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words := parseWordlist(tt.data)
+			for i := range unix {
+				if words[i] != unix[i] {
+					t.Errorf("entry %s = %q, want %q", indexToRoll(i, diceCount), words[i], unix[i])
+				}
+			}
+		})
+	}
+}
+
+// entryCount returns the number of rolls a roll-indexed word slice has an
+// entry for
+func entryCount(words []string) int {
+	n := 0
+	for _, word := range words {
+		if word != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// builtin returns the built-in wordlist for lang.
+func builtin(lang Language) *Wordlist {
+	wl, _ := lookupWordlist(lang)
+	return wl
+}
+
+// TestBuiltinWordlistConcurrentLoad loads the built-in lists from many
+// goroutines at once; run with -race. Every caller must get the same list.
+func TestBuiltinWordlistConcurrentLoad(t *testing.T) {
+	const goroutines = 16
+	got := make([][]*Wordlist, goroutines)
+	var wg sync.WaitGroup
+	for g := range got {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for _, b := range builtinWordlists {
+				got[g] = append(got[g], builtin(b.lang))
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	for g := range got {
+		for i, wl := range got[g] {
+			if wl == nil || wl != got[0][i] {
+				t.Fatalf("goroutine %d got %p for %s, want %p", g, wl, builtinWordlists[i].name, got[0][i])
+			}
+		}
 	}
 }
 
 // Benchmark tests
+
+// BenchmarkParseBuiltin measures the first-use cost of each built-in list,
+// which a program only pays for the languages it uses.
+func BenchmarkParseBuiltin(b *testing.B) {
+	for i, bw := range builtinWordlists {
+		b.Run(bw.name, func(b *testing.B) {
+			for n := 0; n < b.N; n++ {
+				parseBuiltin(i)
+			}
+		})
+	}
+}
+
 func BenchmarkGenerate(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		_, err := Generate(6)
@@ -290,9 +395,45 @@ func BenchmarkGenerate(b *testing.B) {
 	}
 }
 
+// BenchmarkGenerateLong generates the longest passphrase the CLI allows,
+// where the number of reads from the random source dominates.
+func BenchmarkGenerateLong(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := GenerateWithOptions(20, WithSeparator("-")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkGenerateParallel generates from many goroutines at once. Run it
+// with -race (as `just test` does for the tests) to catch any shared mutable
+// state sneaking into the generation path; ns/op should also stay roughly
+// flat as -cpu grows, since there is no lock contention.
+func BenchmarkGenerateParallel(b *testing.B) {
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := GenerateWithLanguage(6, LanguageMixed); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+// BenchmarkGenerateTo compares with BenchmarkGenerate; run both with
+// -benchmem to see the saved allocations.
+func BenchmarkGenerateTo(b *testing.B) {
+	var buf bytes.Buffer
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := GenerateTo(&buf, 6, LanguageEnglish, ""); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func BenchmarkRollDice(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := rollDice()
+		_, err := rollDice(rand.Reader)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -301,7 +442,30 @@ func BenchmarkRollDice(b *testing.B) {
 
 func BenchmarkRollFiveDice(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_, err := rollFiveDice()
+		_, err := rollFiveDice(rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRollNDice compares with BenchmarkRollFiveDice: generation rolls
+// straight to a wordlist index and only builds the roll string for words
+// it keeps.
+func BenchmarkRollNDice(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, err := rollNDice(rand.Reader, diceCount)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkRollUniform compares with BenchmarkRollNDice: generation draws
+// a whole roll at once from two bytes instead of a byte per die.
+func BenchmarkRollUniform(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, err := rollUniform(rand.Reader, rollCombinations)
 		if err != nil {
 			b.Fatal(err)
 		}
@@ -317,6 +481,31 @@ func BenchmarkGetWord(b *testing.B) {
 	}
 }
 
+// TestGenerateConcurrent generates from several goroutines at once so the
+// race detector can flag shared mutable state in the generation path
+func TestGenerateConcurrent(t *testing.T) {
+	const goroutines = 8
+	errs := make(chan error, goroutines)
+
+	for g := 0; g < goroutines; g++ {
+		go func() {
+			for i := 0; i < 20; i++ {
+				if _, err := GenerateWithOptions(6, WithLanguage(LanguageMixed), WithSeparator("-")); err != nil {
+					errs <- err
+					return
+				}
+			}
+			errs <- nil
+		}()
+	}
+
+	for g := 0; g < goroutines; g++ {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent generation failed: %v", err)
+		}
+	}
+}
+
 // Test for randomness distribution
 func TestRandomnessDistribution(t *testing.T) {
 	if testing.Short() {
@@ -328,7 +517,7 @@ func TestRandomnessDistribution(t *testing.T) {
 	iterations := 6000
 
 	for i := 0; i < iterations; i++ {
-		result, err := rollDice()
+		result, err := rollDice(rand.Reader)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -383,6 +572,16 @@ func TestCapitalize(t *testing.T) {
 		{"über", "Über"},
 		{"île", "Île"},
 		{"ñandu", "Ñandu"},
+		// Romanian initial letters with diacritics
+		{"ăla", "Ăla"},
+		{"âncă", "Âncă"},
+		{"înger", "Înger"},
+		{"șarpe", "Șarpe"},
+		{"țară", "Țară"},
+		{"şcoală", "Şcoală"},
+		// Digraphs take their title case form, not upper case
+		{"ǆep", "ǅep"},
+		{"ǉubav", "ǈubav"},
 	}
 
 	for _, tt := range tests {
@@ -396,6 +595,11 @@ func TestCapitalize(t *testing.T) {
 			}
 		})
 	}
+
+	// An invalid leading byte is left alone instead of being mangled further
+	if got := capitalize("\xffword"); got != "\xffword" {
+		t.Errorf("capitalize(%q) = %q, want input unchanged", "\xffword", got)
+	}
 }
 
 // Test that generated words are capitalized
@@ -476,6 +680,72 @@ func TestGenerateWithLanguageAndSeparator(t *testing.T) {
 	}
 }
 
+// TestGenerateWords tests that the individual words are returned unjoined
+func TestGenerateByLength(t *testing.T) {
+	for _, target := range []int{1, 12, 30, 100} {
+		for _, lang := range []Language{LanguageEnglish, LanguageRomanian} {
+			passphrase, entropy, err := GenerateByLength(target, lang)
+			if err != nil {
+				t.Fatalf("GenerateByLength(%d, %v) error = %v", target, lang, err)
+			}
+			words, ok := splitCapitalized(passphrase)
+			if !ok {
+				t.Fatalf("GenerateByLength(%d, %v) = %q, want capitalized words", target, lang, passphrase)
+			}
+			n := utf8.RuneCountInString(passphrase)
+			last := utf8.RuneCountInString(words[len(words)-1])
+			if n < target || n-last >= target {
+				t.Errorf("GenerateByLength(%d, %v) = %q, want the first word reaching %d characters to end it", target, lang, passphrase, target)
+			}
+			if want := EntropyForLanguage(len(words), lang); entropy != want {
+				t.Errorf("GenerateByLength(%d, %v) entropy = %f, want %f for %d words", target, lang, entropy, want, len(words))
+			}
+		}
+	}
+
+	if _, _, err := GenerateByLength(0, LanguageEnglish); err == nil {
+		t.Error("GenerateByLength(0) should return an error")
+	}
+	if _, _, err := GenerateByLength(30, Language(99)); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("GenerateByLength() with an unsupported language error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
+
+func TestGenerateWords(t *testing.T) {
+	tests := []struct {
+		name      string
+		wordCount int
+		lang      Language
+		wantErr   bool
+	}{
+		{"English", 6, LanguageEnglish, false},
+		{"Romanian", 4, LanguageRomanian, false},
+		{"Mixed", 5, LanguageMixed, false},
+		{"invalid word count", 0, LanguageEnglish, true},
+		{"unsupported language", 4, Language(99), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			words, err := GenerateWords(tt.wordCount, tt.lang)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateWords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(words) != tt.wordCount {
+				t.Errorf("GenerateWords() returned %d words, want %d", len(words), tt.wordCount)
+			}
+			for _, word := range words {
+				if word == "" || word[0] < 'A' || word[0] > 'Z' {
+					t.Errorf("GenerateWords() word %q is empty or not capitalized", word)
+				}
+			}
+		})
+	}
+}
+
 // TestGenerateWithRollsAndLanguage tests roll generation with different languages
 func TestGenerateWithRollsAndLanguage(t *testing.T) {
 	tests := []struct {
@@ -509,6 +779,29 @@ func TestGenerateWithRollsAndLanguage(t *testing.T) {
 	}
 }
 
+func TestGenerateRollsOnly(t *testing.T) {
+	rolls, err := GenerateRollsOnly(6)
+	if err != nil {
+		t.Fatalf("GenerateRollsOnly() error = %v", err)
+	}
+	if len(rolls) != 6 {
+		t.Fatalf("GenerateRollsOnly() returned %d rolls, want 6", len(rolls))
+	}
+	for _, roll := range rolls {
+		if !isValidRoll(roll, diceCount) {
+			t.Errorf("GenerateRollsOnly() returned invalid roll %q", roll)
+		}
+		// The rolls look up like any other
+		if _, err := WordAt(roll, LanguageEnglish); err != nil {
+			t.Errorf("WordAt(%q) error = %v", roll, err)
+		}
+	}
+
+	if _, err := GenerateRollsOnly(0); err == nil {
+		t.Error("GenerateRollsOnly(0) should return an error")
+	}
+}
+
 // TestGenerateWithRollsLanguageAndSeparator covers the separator-aware
 // variant that the CLI uses for `-r` + `-s` combined, instead of the old
 // approach of reconstructing word boundaries from the capitalized string.
@@ -589,11 +882,137 @@ func TestWordlistSizeByLanguage(t *testing.T) {
 
 // TestRomanianWordlistLoaded tests that Romanian wordlist is properly loaded
 func TestRomanianWordlistLoaded(t *testing.T) {
-	if len(wordlistRomanian) == 0 {
+	if entryCount(builtin(LanguageRomanian).words) == 0 {
 		t.Error("Romanian wordlist is empty")
 	}
-	if len(wordlistRomanian) < 7000 {
-		t.Errorf("Romanian wordlist has only %d words, expected around 7776", len(wordlistRomanian))
+	if n := entryCount(builtin(LanguageRomanian).words); n < 7000 {
+		t.Errorf("Romanian wordlist has only %d words, expected around 7776", n)
+	}
+}
+
+// TestIndexToRoll tests the index to dice roll conversion at the boundaries
+func TestIndexToRoll(t *testing.T) {
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{0, "11111"},
+		{1, "11112"},
+		{5, "11116"},
+		{6, "11121"},
+		{7775, "66666"},
+	}
+
+	for _, tt := range tests {
+		if got := indexToRoll(tt.index, diceCount); got != tt.want {
+			t.Errorf("indexToRoll(%d) = %q, want %q", tt.index, got, tt.want)
+		}
+		if got, err := IndexToRoll(tt.index); err != nil || got != tt.want {
+			t.Errorf("IndexToRoll(%d) = %q, %v, want %q", tt.index, got, err, tt.want)
+		}
+	}
+	for _, i := range []int{-1, rollCombinations} {
+		if _, err := IndexToRoll(i); err == nil {
+			t.Errorf("IndexToRoll(%d) should return an error", i)
+		}
+	}
+}
+
+// TestRollToIndex checks that rollToIndex inverts indexToRoll for every
+// roll and rejects malformed ones
+func TestRollToIndex(t *testing.T) {
+	for i := 0; i < rollCombinations; i++ {
+		if got, ok := rollToIndex(indexToRoll(i, diceCount), diceCount); !ok || got != i {
+			t.Fatalf("rollToIndex(%q) = %d, %v, want %d, true", indexToRoll(i, diceCount), got, ok, i)
+		}
+	}
+	for _, roll := range []string{"", "1111", "111111", "11107", "1111a"} {
+		if _, ok := rollToIndex(roll, diceCount); ok {
+			t.Errorf("rollToIndex(%q) should fail", roll)
+		}
+		if _, err := RollToIndex(roll); err == nil {
+			t.Errorf("RollToIndex(%q) should return an error", roll)
+		}
+	}
+	if got, err := RollToIndex("66666"); err != nil || got != 7775 {
+		t.Errorf("RollToIndex(66666) = %d, %v, want 7775", got, err)
+	}
+}
+
+// TestWordlistChecksum pins the embedded files: a change to any of them
+// must be deliberate, updating the checksum here along with it.
+func TestWordlistChecksum(t *testing.T) {
+	tests := []struct {
+		lang Language
+		want string
+	}{
+		{LanguageEnglish, "addd35536511597a02fa0a9ff1e5284677b8883b83e986e43f15a3db996b903e"},
+		{LanguageRomanian, "b1f919fb54c2a23ba8bfc552d2c352224963842dec389d02902dc583c721d5c3"},
+		{LanguageReinhold, "62a1f0bf0939b5c8e65369041853f18c580e0f4c6ab7bf866d70cef63dd40204"},
+		{LanguageBIP39English, "bb4ed7d492b961de3497fbf49746d8c5f292289db9274dfb4ca0badf30a24077"},
+		{LanguageMixed, ""},
+		{Language(99), ""},
+	}
+	for _, tt := range tests {
+		if got := WordlistChecksum(tt.lang); got != tt.want {
+			t.Errorf("WordlistChecksum(%v) = %q, want %q", tt.lang, got, tt.want)
+		}
+	}
+}
+
+// TestValidateWordlist checks that the embedded wordlists pass validation
+// and that unknown languages are rejected
+func TestValidateWordlist(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		if err := ValidateWordlist(lang); err != nil {
+			t.Errorf("ValidateWordlist(%v) error = %v", lang, err)
+		}
+	}
+
+	if err := ValidateWordlist(Language(99)); err == nil {
+		t.Error("ValidateWordlist(99) should return an error")
+	}
+}
+
+// TestValidateWordlistBroken runs validateWordlist against deliberately
+// broken copies of a complete wordlist
+func TestValidateWordlistBroken(t *testing.T) {
+	complete := func() []string {
+		list := make([]string, rollCombinations)
+		for i := range list {
+			list[i] = fmt.Sprintf("word%d", i)
+		}
+		return list
+	}
+
+	tests := []struct {
+		name   string
+		mutate func([]string) []string
+	}{
+		{"truncated", func(l []string) []string { return l[:len(l)-1] }},
+		{"missing roll", func(l []string) []string { l[3000] = ""; return l }},
+		{"duplicate word", func(l []string) []string { l[3000] = l[0]; return l }},
+		{"non-ASCII word", func(l []string) []string { l[3000] = "café"; return l }},
+	}
+
+	if err := validateWordlist("test", complete(), true); err != nil {
+		t.Fatalf("validateWordlist() on complete list error = %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list := tt.mutate(complete())
+			if err := validateWordlist("test", list, true); err == nil {
+				t.Errorf("validateWordlist() should have failed for %s list", tt.name)
+			}
+		})
+	}
+
+	// Non-ASCII is only rejected when requested (Romanian allows it)
+	list := complete()
+	list[3000] = "café"
+	if err := validateWordlist("test", list, false); err != nil {
+		t.Errorf("validateWordlist() without ASCII check error = %v", err)
 	}
 }
 
@@ -682,7 +1101,7 @@ func TestIsValidRoll(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := isValidRoll(tt.roll)
+			got := isValidRoll(tt.roll, diceCount)
 			if got != tt.want {
 				t.Errorf("isValidRoll(%q) = %v, want %v", tt.roll, got, tt.want)
 			}
@@ -708,9 +1127,14 @@ func TestParseWordlistValidation(t *testing.T) {
 			wantPanic: true,
 		},
 		{
-			name:      "invalid - too many fields",
+			name:      "valid - multi-word entry",
 			data:      "11111 word1 extra\n22222 word2",
-			wantPanic: true,
+			wantPanic: false,
+		},
+		{
+			name:      "valid - comments",
+			data:      "# header\n11111 word1\n  # indented comment\n22222 word2",
+			wantPanic: false,
 		},
 		{
 			name:      "invalid - bad roll (has 0)",
@@ -737,6 +1161,21 @@ func TestParseWordlistValidation(t *testing.T) {
 			data:      "11111 word1\n11111 word2",
 			wantPanic: true,
 		},
+		{
+			name:      "invalid - empty",
+			data:      "",
+			wantPanic: true,
+		},
+		{
+			name:      "invalid - whitespace only",
+			data:      " \n\t\r\n\n",
+			wantPanic: true,
+		},
+		{
+			name:      "invalid - comments only",
+			data:      "# header\n",
+			wantPanic: true,
+		},
 		{
 			name:      "valid - with empty lines",
 			data:      "11111 word1\n\n22222 word2\n\n",
@@ -760,3 +1199,132 @@ func TestParseWordlistValidation(t *testing.T) {
 		})
 	}
 }
+
+// FuzzParseWordlist feeds arbitrary data to the wordlist parser, which
+// handles untrusted files via LoadWordlist, in both strict and lenient
+// mode. It must never panic, and every entry it returns must sit at a valid
+// roll and be a trimmed, single-line word.
+func FuzzParseWordlist(f *testing.F) {
+	f.Add("11111 word1\n22222 word2\n")
+	f.Add("# header\n11111 ice cream\n\n  # indented\n66666 end")
+	f.Add("11111 word\r\n11112 \tmixed \t space\r\n")
+	f.Add("11111\x00 nul\n1111 short\n111111 long\n11111 duplicate\n")
+	f.Add("\xff\xfe 11111 \u00a0word\u2028\n")
+
+	f.Fuzz(func(t *testing.T, data string) {
+		strictWords, strictN, _, strictErr := readWordlist(data, true)
+		words, n, _, err := readWordlist(data, false)
+		if err != nil {
+			t.Fatalf("lenient readWordlist() error = %v", err)
+		}
+		dice := diceForRolls(len(words))
+		if dice < 1 || dice > maxDiceCount {
+			t.Fatalf("readWordlist() returned %d slots, want 6^n for 1 to %d dice", len(words), maxDiceCount)
+		}
+
+		count := 0
+		for i, word := range words {
+			if word == "" {
+				continue
+			}
+			count++
+			if _, ok := rollToIndex(indexToRoll(i, dice), dice); !ok {
+				t.Errorf("entry %d has no valid roll", i)
+			}
+			if word != strings.TrimSpace(word) || strings.ContainsAny(word, "\r\n") {
+				t.Errorf("entry %s = %q, want a trimmed single-line word", indexToRoll(i, dice), word)
+			}
+		}
+		if count != n {
+			t.Errorf("readWordlist() reported %d entries, found %d", n, count)
+		}
+
+		// Whatever strict mode accepts, lenient mode must read identically.
+		if strictErr == nil {
+			if strictN != n {
+				t.Errorf("strict mode read %d entries, lenient %d", strictN, n)
+			}
+			for i := range strictWords {
+				if strictWords[i] != words[i] {
+					t.Errorf("entry %s: strict %q, lenient %q", indexToRoll(i, dice), strictWords[i], words[i])
+				}
+			}
+		}
+	})
+}
+
+// errWriter is an io.Writer that always fails.
+type errWriter struct{}
+
+func (errWriter) Write([]byte) (int, error) {
+	return 0, fmt.Errorf("write failed")
+}
+
+func TestGenerateTo(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		var buf bytes.Buffer
+		if err := GenerateTo(&buf, 5, lang, "-"); err != nil {
+			t.Fatalf("GenerateTo(%v) error = %v", lang, err)
+		}
+		if ok, _ := VerifyPassphrase(buf.String(), 5, lang); !ok {
+			t.Errorf("GenerateTo(%v) wrote %q, want 5 words", lang, buf.String())
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := GenerateTo(&buf, 0, LanguageEnglish, ""); err == nil {
+		t.Error("GenerateTo() with word count 0 should return an error")
+	}
+	if err := GenerateTo(&buf, 4, Language(99), ""); err == nil {
+		t.Error("GenerateTo() with an unsupported language should return an error")
+	}
+	if buf.Len() != 0 {
+		t.Errorf("GenerateTo() wrote %q on error, want nothing", buf.String())
+	}
+	if err := GenerateTo(errWriter{}, 4, LanguageEnglish, ""); err == nil {
+		t.Error("GenerateTo() should return the writer's error")
+	}
+}
+
+func TestAppendCapitalized(t *testing.T) {
+	for _, word := range []string{"hello", "", "țară", "Über", "\xffword"} {
+		if got, want := string(appendCapitalized([]byte("x"), word)), "x"+capitalize(word); got != want {
+			t.Errorf("appendCapitalized(%q) = %q, want %q", word, got, want)
+		}
+	}
+}
+
+func TestMustGenerate(t *testing.T) {
+	if ok, _ := VerifyPassphrase(MustGenerate(6), 6, LanguageEnglish); !ok {
+		t.Error("MustGenerate(6) did not return 6 English words")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustGenerate(0) should panic")
+		}
+	}()
+	MustGenerate(0)
+}
+
+func TestGenerateBytes(t *testing.T) {
+	b, err := GenerateBytes(6)
+	if err != nil {
+		t.Fatalf("GenerateBytes(6) error = %v", err)
+	}
+	if ok, _ := VerifyPassphrase(string(b), 6, LanguageEnglish); !ok {
+		t.Errorf("GenerateBytes(6) = %q, want 6 English words", b)
+	}
+
+	Wipe(b)
+	for i, c := range b {
+		if c != 0 {
+			t.Fatalf("byte %d = %#x after Wipe, want 0", i, c)
+		}
+	}
+	Wipe(nil)
+
+	if _, err := GenerateBytes(0); err == nil {
+		t.Error("GenerateBytes(0) should return an error")
+	}
+}
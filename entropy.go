@@ -0,0 +1,111 @@
+package diceware
+
+import (
+	"math"
+	"strings"
+)
+
+// EntropyBreakdown itemizes where the entropy of a passphrase comes from, so
+// a UI can show more than a single number once options add randomness
+// beyond the words themselves. All figures are in bits.
+type EntropyBreakdown struct {
+	// Words is the entropy of the drawn words, accounting for list weights,
+	// WithBlocklist and WithUniqueWords.
+	Words float64
+	// Casing is the entropy added by randomized casing: with CapRandom, a
+	// bit for each word that has a letter to capitalize. Casing applied the
+	// same way to every passphrase (the default, WithCapitalizer) adds none.
+	Casing float64
+	// Decorations is the entropy added by extra random elements such as the
+	// WithNumberWord number, WithSeparatorRandom separators and
+	// WithConnectorWords connectors.
+	Decorations float64
+	// Total is the sum of the components.
+	Total float64
+}
+
+// breakdown computes the EntropyBreakdown for wordCount words. Only valid
+// after validate succeeds.
+func (o *options) breakdown(wordCount int) EntropyBreakdown {
+	b := EntropyBreakdown{
+		Words:       o.wordBits(wordCount),
+		Decorations: float64(o.numDigits) * math.Log2(10),
+	}
+	if o.capitalize == nil {
+		random := 0
+		for i := 0; i < wordCount; i++ {
+			if o.caseMode(i) == CapRandom {
+				random++
+			}
+		}
+		if random > 0 {
+			b.Casing = float64(random) * o.casedRate()
+		}
+	}
+	if o.randomSeps != nil {
+		tokens := wordCount
+		if o.numDigits > 0 {
+			tokens++
+		}
+		gaps := o.gapCount(tokens)
+		b.Decorations += float64(gaps) * math.Log2(float64(len(o.randomSeps)))
+	}
+	if o.connectors && wordCount > 0 {
+		tokens := wordCount
+		if o.numDigits > 0 {
+			tokens++
+		}
+		b.Decorations += float64(tokens-1) * math.Log2(float64(len(connectorWords)))
+	}
+	b.Total = b.Words + b.Casing + b.Decorations
+	return b
+}
+
+// casedRate returns the probability that a drawn word changes when
+// capitalized, i.e. that CapRandom's coin flip shows in the passphrase.
+// Words like "@" or "1984" have no letter to capitalize.
+func (o *options) casedRate() float64 {
+	lists, weights := o.sources()
+	accept := acceptRate(lists, weights)
+	if accept == 0 {
+		return 0
+	}
+
+	rate := 0.0
+	for i, wl := range lists {
+		w := 1 / float64(len(lists))
+		if weights != nil {
+			w = weights[i]
+		}
+		if w == 0 {
+			continue
+		}
+		cased := wl.mass(func(word string) bool {
+			return capitalize(word) != strings.ToLower(word)
+		})
+		rate += w * cased / accept
+	}
+	return rate
+}
+
+// EntropyBreakdownWithOptions is like EntropyWithOptions but itemizes the
+// result. It returns a zero EntropyBreakdown if the options are invalid.
+func EntropyBreakdownWithOptions(wordCount int, opts ...Option) EntropyBreakdown {
+	o := newOptions(opts...)
+	if o.validate() != nil {
+		return EntropyBreakdown{}
+	}
+	return o.breakdown(wordCount)
+}
+
+// GenerateWithBreakdown is like GenerateWithOptions but also returns the
+// entropy breakdown for the options used, saving a separate
+// EntropyBreakdownWithOptions call with the same options.
+func GenerateWithBreakdown(wordCount int, opts ...Option) (string, EntropyBreakdown, error) {
+	o := newOptions(opts...)
+	passphrase, _, _, err := generate(wordCount, o)
+	if err != nil {
+		return "", EntropyBreakdown{}, err
+	}
+	return passphrase, o.breakdown(wordCount), nil
+}
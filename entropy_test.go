@@ -0,0 +1,47 @@
+package diceware
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEntropyBreakdown(t *testing.T) {
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	b := EntropyBreakdownWithOptions(6)
+	if !near(b.Words, Entropy(6)) || b.Casing != 0 || b.Decorations != 0 || !near(b.Total, Entropy(6)) {
+		t.Errorf("default breakdown = %+v, want only word entropy %f", b, Entropy(6))
+	}
+
+	opts := []Option{WithLanguage(LanguageMixed), WithNumberWord(3)}
+	b = EntropyBreakdownWithOptions(6, opts...)
+	if !near(b.Words, EntropyForLanguage(6, LanguageMixed)) {
+		t.Errorf("Words = %f, want %f", b.Words, EntropyForLanguage(6, LanguageMixed))
+	}
+	if !near(b.Decorations, 3*math.Log2(10)) {
+		t.Errorf("Decorations = %f, want %f", b.Decorations, 3*math.Log2(10))
+	}
+	if !near(b.Total, b.Words+b.Casing+b.Decorations) || !near(b.Total, EntropyWithOptions(6, opts...)) {
+		t.Errorf("Total = %f, want the sum of components and EntropyWithOptions", b.Total)
+	}
+
+	if got := EntropyBreakdownWithOptions(6, WithLanguage(Language(99))); got != (EntropyBreakdown{}) {
+		t.Errorf("breakdown for invalid options = %+v, want zero", got)
+	}
+}
+
+func TestGenerateWithBreakdown(t *testing.T) {
+	passphrase, b, err := GenerateWithBreakdown(5, WithSeparator("-"), WithNumberWord(2))
+	if err != nil {
+		t.Fatalf("GenerateWithBreakdown() error = %v", err)
+	}
+	if passphrase == "" {
+		t.Error("GenerateWithBreakdown() returned an empty passphrase")
+	}
+	if want := EntropyBreakdownWithOptions(5, WithNumberWord(2)); b != want {
+		t.Errorf("GenerateWithBreakdown() breakdown = %+v, want %+v", b, want)
+	}
+	if _, _, err := GenerateWithBreakdown(0); err == nil {
+		t.Error("GenerateWithBreakdown(0) should return an error")
+	}
+}
@@ -0,0 +1,74 @@
+package diceware
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Errors returned (wrapped) by the package, for use with errors.Is. For
+// example, a server can reject ErrInvalidWordCount and
+// ErrUnsupportedLanguage as bad input while retrying on ErrRandomSource:
+//
+//	passphrase, err := diceware.GenerateWithLanguage(n, lang)
+//	switch {
+//	case errors.Is(err, diceware.ErrInvalidWordCount),
+//	    errors.Is(err, diceware.ErrUnsupportedLanguage):
+//	    http.Error(w, err.Error(), http.StatusBadRequest)
+//	case errors.Is(err, diceware.ErrRandomSource):
+//	    // transient, retry
+//	}
+var (
+	// ErrInvalidWordCount is returned for a word count below 1.
+	ErrInvalidWordCount = errors.New("invalid word count")
+
+	// ErrUnsupportedLanguage is returned for a Language that is neither
+	// built in nor registered.
+	ErrUnsupportedLanguage = errors.New("unsupported language")
+
+	// ErrRandomSource is returned when reading from the random source
+	// fails, after the retries WithRandAttempts allows. The source's own
+	// error is wrapped as well.
+	ErrRandomSource = errors.New("random source failed")
+
+	// ErrWordNotFound is returned when no usable word can be found: a roll
+	// without a usable entry passed to WordAt, generation running out of
+	// rerolls because nearly every entry is filtered out or used, or a
+	// passphrase SplitPassphrase can't split into words.
+	ErrWordNotFound = errors.New("word not found")
+
+	// ErrMaxAttempts is returned when generation gives up after rerolling
+	// as many times as it may (see WithMaxAttempts), alongside the error
+	// saying what it was looking for and how many attempts it made.
+	ErrMaxAttempts = errors.New("attempt limit reached")
+
+	// ErrEmptyWordlist is returned for a wordlist without a single entry:
+	// an empty file, one of only blank lines or comments, an empty map,
+	// or for LoadWordlistLenient one whose every line was malformed.
+	ErrEmptyWordlist = errors.New("empty wordlist")
+
+	// ErrInsufficientEntropy is returned when a passphrase would have less
+	// entropy than RequireMinEntropy demands.
+	ErrInsufficientEntropy = errors.New("insufficient entropy")
+)
+
+// invalidWordCount returns an ErrInvalidWordCount error for n.
+func invalidWordCount(n int) error {
+	return fmt.Errorf("%w: must be at least 1, got %d", ErrInvalidWordCount, n)
+}
+
+// unsupportedLanguage returns an ErrUnsupportedLanguage error for lang.
+func unsupportedLanguage(lang Language) error {
+	return fmt.Errorf("%w: %v", ErrUnsupportedLanguage, lang)
+}
+
+// insufficientEntropy returns an ErrInsufficientEntropy error for
+// wordCount words giving bits of entropy when required are needed.
+func insufficientEntropy(wordCount int, bits, required float64) error {
+	return fmt.Errorf("%w: %d words give %.1f bits, at least %.1f required", ErrInsufficientEntropy, wordCount, bits, required)
+}
+
+// randomSourceError wraps a failed read from the random source in
+// ErrRandomSource.
+func randomSourceError(err error) error {
+	return fmt.Errorf("%w: %w", ErrRandomSource, err)
+}
@@ -0,0 +1,44 @@
+package diceware
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSentinelErrors(t *testing.T) {
+	_, err := Generate(0)
+	if !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("Generate(0) error = %v, want ErrInvalidWordCount", err)
+	}
+	if _, err := VerifyPassphrase("Abacus", 0, LanguageEnglish); !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("VerifyPassphrase() error = %v, want ErrInvalidWordCount", err)
+	}
+
+	for name, err := range map[string]error{
+		"GenerateWithLanguage": func() error { _, err := GenerateWithLanguage(4, Language(99)); return err }(),
+		"GenerateWithOptions":  func() error { _, err := GenerateWithOptions(4, WithLanguage(Language(99))); return err }(),
+		"WordlistByLanguage":   func() error { _, err := WordlistByLanguage(Language(99)); return err }(),
+		"WordCountForLevel":    func() error { _, err := WordCountForLevel(SecurityHigh, Language(99)); return err }(),
+	} {
+		if !errors.Is(err, ErrUnsupportedLanguage) {
+			t.Errorf("%s() error = %v, want ErrUnsupportedLanguage", name, err)
+		}
+	}
+
+	ro, _ := WordlistByLanguage(LanguageRomanian)
+	for i, word := range ro.words {
+		if !ro.accepts(word) {
+			if _, err := WordAt(indexToRoll(i, diceCount), LanguageRomanian); !errors.Is(err, ErrWordNotFound) {
+				t.Errorf("WordAt() on filler entry %q error = %v, want ErrWordNotFound", word, err)
+			}
+			break
+		}
+	}
+
+	// A failing random source is reported as such, wrapping its own error
+	o := newOptions(WithLanguage(LanguageMixed), WithNumberWord(2))
+	o.rand = failingReader{}
+	if _, _, _, err := generate(4, o); !errors.Is(err, ErrRandomSource) || !errors.Is(err, errReadFailed) {
+		t.Errorf("generate() with a failing reader error = %v, want ErrRandomSource wrapping the read error", err)
+	}
+}
@@ -4,21 +4,20 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+
+	"github.com/cleonte/go-diceware"
 )
 
 func main() {
 	// Parameters
 	students := 70
 	words := 4
-	wordlistSize := 7776 // 6^5 for Diceware
+	lang := diceware.LanguageEnglish
+	wordlistSize := diceware.WordlistSizeByLanguage(lang)
 
 	// Calculate total possible passphrases
 	// For 4 words: 7776^4
-	totalPassphrases := new(big.Int).Exp(
-		big.NewInt(int64(wordlistSize)),
-		big.NewInt(int64(words)),
-		nil,
-	)
+	totalPassphrases := diceware.AttackKeyspace(words, lang)
 
 	fmt.Println("=== Diceware Collision Probability Analysis ===")
 	fmt.Println()
@@ -31,32 +30,16 @@ func main() {
 	fmt.Println()
 
 	// Calculate entropy
-	entropy := float64(words) * math.Log2(float64(wordlistSize))
+	entropy := diceware.EntropyForLanguage(words, lang)
 	fmt.Printf("Entropy: %.1f bits\n", entropy)
 	fmt.Println()
 
 	// Birthday paradox calculation
 	// Probability of NO collision = (N/N) * ((N-1)/N) * ((N-2)/N) * ... * ((N-k+1)/N)
-	// where N = total passphrases, k = number of students
-
-	// Use logarithms to avoid overflow
-	// P(no collision) = exp(sum(log((N-i)/N))) for i from 0 to k-1
-
-	N := new(big.Float).SetInt(totalPassphrases)
-	logProbNoCollision := 0.0
-
-	for i := 0; i < students; i++ {
-		// Calculate (N - i) / N
-		numerator := new(big.Float).Sub(N, big.NewFloat(float64(i)))
-		ratio, _ := new(big.Float).Quo(numerator, N).Float64()
-
-		if ratio > 0 {
-			logProbNoCollision += math.Log(ratio)
-		}
-	}
-
-	probNoCollision := math.Exp(logProbNoCollision)
-	probCollision := 1.0 - probNoCollision
+	// where N = total passphrases, k = number of students.
+	// CollisionProbability sums the logarithms to avoid overflow.
+	probCollision := diceware.CollisionProbability(students, words, lang)
+	probNoCollision := 1.0 - probCollision
 
 	fmt.Println("=== Results ===")
 	fmt.Printf("Probability of NO collision: %.10f (%.2e)\n", probNoCollision, probNoCollision)
@@ -70,27 +53,8 @@ func main() {
 	// For comparison, calculate for different word counts
 	fmt.Println("=== Comparison with different word counts ===")
 	for w := 3; w <= 8; w++ {
-		totalPass := new(big.Int).Exp(
-			big.NewInt(int64(wordlistSize)),
-			big.NewInt(int64(w)),
-			nil,
-		)
-
-		NComp := new(big.Float).SetInt(totalPass)
-		logProbNo := 0.0
-
-		for i := 0; i < students; i++ {
-			numerator := new(big.Float).Sub(NComp, big.NewFloat(float64(i)))
-			ratio, _ := new(big.Float).Quo(numerator, NComp).Float64()
-
-			if ratio > 0 {
-				logProbNo += math.Log(ratio)
-			}
-		}
-
-		probNo := math.Exp(logProbNo)
-		probCol := 1.0 - probNo
-		ent := float64(w) * math.Log2(float64(wordlistSize))
+		probCol := diceware.CollisionProbability(students, w, lang)
+		ent := diceware.EntropyForLanguage(w, lang)
 
 		fmt.Printf("%d words (%.1f bits): %.2e (%.8f%%)\n",
 			w, ent, probCol, probCol*100)
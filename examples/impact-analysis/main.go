@@ -81,38 +81,37 @@ func main() {
 	fmt.Println()
 
 	// Memory usage
-	// The wordlist is loaded into memory as a map
-	// Each entry: string key (5 bytes) + string value (avg 8 bytes) + map overhead
+	// The wordlist is loaded into memory as a slice indexed by dice roll
+	// Each entry: string header (16 bytes) + string value (avg 8 bytes)
 	wordCount := 7776
-	avgKeySize := 5   // "12345"
 	avgValueSize := 8 // average word length
-	mapOverhead := 16 // approximate overhead per entry in Go map
+	headerSize := 16  // pointer + length of a Go string
 
-	totalMapMemory := wordCount * (avgKeySize + avgValueSize + mapOverhead)
+	totalSliceMemory := wordCount * (headerSize + avgValueSize)
 
 	fmt.Printf("   Wordlist entries: %d\n", wordCount)
-	fmt.Printf("   Estimated map memory: ~%d bytes (%.1f KB)\n", totalMapMemory, float64(totalMapMemory)/1024)
+	fmt.Printf("   Estimated slice memory: ~%d bytes (%.1f KB)\n", totalSliceMemory, float64(totalSliceMemory)/1024)
 	fmt.Printf("   Additional runtime overhead: ~10-20 KB\n")
 	fmt.Println()
 	fmt.Printf("   Total memory impact: ~%.1f KB (%.2f MB)\n",
-		float64(totalMapMemory)/1024+15,
-		(float64(totalMapMemory)/1024+15)/1024)
+		float64(totalSliceMemory)/1024+15,
+		(float64(totalSliceMemory)/1024+15)/1024)
 
 	fmt.Println()
 	fmt.Println("3. STARTUP TIME IMPACT")
 	fmt.Println()
-	fmt.Println("   The wordlist is loaded once at program startup via init()")
+	fmt.Println("   Each wordlist is loaded once, on first use (sync.Once)")
 	fmt.Println("   - Parsing ~7,776 entries from embedded string")
-	fmt.Println("   - Building the map structure")
-	fmt.Println("   - Estimated impact: <5ms on modern hardware")
-	fmt.Println("   - This is a ONE-TIME cost at program initialization")
+	fmt.Println("   - Filling the roll-indexed word slice")
+	fmt.Println("   - About 2ms per list on modern hardware (BenchmarkParseBuiltin)")
+	fmt.Println("   - A ONE-TIME cost, paid only for the languages a program uses")
 
 	fmt.Println()
 	fmt.Println("4. RUNTIME PERFORMANCE")
 	fmt.Println()
 	fmt.Println("   Generating a passphrase:")
 	fmt.Println("   - Uses crypto/rand for secure random numbers")
-	fmt.Println("   - Map lookups are O(1)")
+	fmt.Println("   - Word lookups index a slice by the roll, O(1)")
 	fmt.Println("   - String operations are minimal")
 	fmt.Println("   - Estimated time: <1ms for a 6-word passphrase")
 
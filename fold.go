@@ -0,0 +1,64 @@
+package diceware
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// asciiFolds maps letters with diacritics to their base ASCII letters for
+// WithASCIIFold. It covers Romanian (including the legacy cedilla forms of
+// ș and ț that older keyboards and fonts produce) plus the other Latin
+// letters found in European Diceware lists.
+var asciiFolds = map[rune]string{
+	// Romanian
+	'ă': "a", 'Ă': "A", 'â': "a", 'Â': "A", 'î': "i", 'Î': "I",
+	'ș': "s", 'Ș': "S", 'ş': "s", 'Ş': "S",
+	'ț': "t", 'Ț': "T", 'ţ': "t", 'Ţ': "T",
+
+	// Other Latin letters
+	'á': "a", 'Á': "A", 'à': "a", 'À': "A", 'ä': "a", 'Ä': "A",
+	'å': "a", 'Å': "A", 'ã': "a", 'Ã': "A", 'æ': "ae", 'Æ': "AE",
+	'ç': "c", 'Ç': "C",
+	'é': "e", 'É': "E", 'è': "e", 'È': "E", 'ê': "e", 'Ê': "E", 'ë': "e", 'Ë': "E",
+	'í': "i", 'Í': "I", 'ì': "i", 'Ì': "I", 'ï': "i", 'Ï': "I",
+	'ñ': "n", 'Ñ': "N",
+	'ó': "o", 'Ó': "O", 'ò': "o", 'Ò': "O", 'ô': "o", 'Ô': "O",
+	'ö': "o", 'Ö': "O", 'õ': "o", 'Õ': "O", 'ø': "o", 'Ø': "O",
+	'ß': "ss",
+	'ú': "u", 'Ú': "U", 'ù': "u", 'Ù': "U", 'û': "u", 'Û': "U", 'ü': "u", 'Ü': "U",
+	'ý': "y", 'Ý': "Y", 'ÿ': "y",
+}
+
+// foldASCII transliterates the letters in asciiFolds to ASCII. It returns
+// an error if word contains a non-ASCII character the table doesn't cover,
+// rather than letting it through to a system that can't store it.
+func foldASCII(word string) (string, error) {
+	if isASCII(word) {
+		return word, nil
+	}
+
+	var b strings.Builder
+	b.Grow(len(word))
+	for _, r := range word {
+		switch folded, ok := asciiFolds[r]; {
+		case r < utf8.RuneSelf:
+			b.WriteRune(r)
+		case ok:
+			b.WriteString(folded)
+		default:
+			return "", fmt.Errorf("word %q has a character with no ASCII equivalent: %q", word, r)
+		}
+	}
+	return b.String(), nil
+}
+
+// isASCII reports whether s consists only of ASCII characters.
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFoldASCII(t *testing.T) {
+	tests := []struct {
+		word    string
+		want    string
+		wantErr bool
+	}{
+		{"abandon", "abandon", false},
+		{"drop-down", "drop-down", false},
+		{"șarpe", "sarpe", false},
+		{"ţară", "tara", false},
+		{"înger", "inger", false},
+		{"ÎNVĂȚĂTOR", "INVATATOR", false},
+		{"bokmål", "bokmal", false},
+		{"straße", "strasse", false},
+		{"日本", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := foldASCII(tt.word)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("foldASCII(%q) error = %v, wantErr %v", tt.word, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("foldASCII(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestWithASCIIFold(t *testing.T) {
+	custom, err := NewWordlist("diacritics", map[string]string{
+		"11111": "șarpe", "11112": "țară", "11113": "înger", "11114": "măr",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passphrase, err := GenerateWithOptions(8, WithWordlists(custom), WithASCIIFold(true), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	allowed := map[string]bool{"Sarpe": true, "Tara": true, "Inger": true, "Mar": true}
+	for _, word := range strings.Split(passphrase, " ") {
+		if !allowed[word] {
+			t.Errorf("word %q is not a folded wordlist entry", word)
+		}
+	}
+
+	// Folding doesn't change the entropy
+	if got, want := EntropyWithOptions(8, WithWordlists(custom), WithASCIIFold(true)), EntropyWithOptions(8, WithWordlists(custom)); got != want {
+		t.Errorf("EntropyWithOptions() with folding = %f, want %f", got, want)
+	}
+
+	unfoldable, _ := NewWordlist("cjk", map[string]string{"11111": "日本"})
+	if _, err := GenerateWithOptions(2, WithWordlists(unfoldable), WithASCIIFold(true)); err == nil {
+		t.Error("GenerateWithOptions() should fail for a word with no ASCII equivalent")
+	}
+}
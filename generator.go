@@ -0,0 +1,137 @@
+package diceware
+
+import (
+	"io"
+	mrand "math/rand"
+	"slices"
+	"sync"
+)
+
+// Generator generates passphrases with a fixed set of options, so the same
+// configuration doesn't have to be passed on every call:
+//
+//	gen := diceware.NewGenerator(diceware.WithSeparator("-"))
+//	passphrase, err := gen.Generate(6)
+//
+// A Generator is safe for concurrent use, including reconfiguring it with
+// Reconfigure or SetLanguage while other goroutines generate: each call
+// uses either the old or the new options, never a mix.
+type Generator struct {
+	mu   sync.RWMutex // guards opts
+	opts []Option     // replaced, never modified in place
+	rand io.Reader    // overrides the options' reader if set
+}
+
+// NewGenerator returns a Generator using opts and cryptographically secure
+// randomness from crypto/rand (or the WithRandReader reader), like
+// GenerateWithOptions.
+func NewGenerator(opts ...Option) *Generator {
+	return &Generator{opts: append([]Option{}, opts...)}
+}
+
+// NewSeededGenerator returns a Generator whose output is fully determined by
+// seed: two generators with the same seed and options produce the same
+// sequence of passphrases, which is useful for documentation examples,
+// tutorials and test fixtures.
+//
+// Do NOT use it for real secrets. The randomness comes from math/rand, and
+// anyone who knows or guesses the seed (at most 64 bits, usually far less)
+// can reproduce every passphrase. Sequences are only guaranteed to be
+// stable for a given version of this package.
+func NewSeededGenerator(seed int64, opts ...Option) *Generator {
+	return &Generator{
+		opts: append([]Option{}, opts...),
+		rand: &seededReader{rng: mrand.New(mrand.NewSource(seed))},
+	}
+}
+
+// Generate creates a passphrase with the specified number of words using
+// the generator's options. Returns an error if wordCount is less than 1, if
+// the options are invalid, or if random number generation fails.
+func (g *Generator) Generate(wordCount int) (string, error) {
+	o := newOptions(g.options()...)
+	if g.rand != nil {
+		o.rand = g.rand
+	}
+	passphrase, _, _, err := generate(wordCount, o)
+	return passphrase, err
+}
+
+// Entropy calculates the bits of entropy for a passphrase of wordCount words
+// generated with the generator's options, see EntropyWithOptions. For a
+// seeded generator the real entropy is that of the seed, not this figure.
+func (g *Generator) Entropy(wordCount int) float64 {
+	return EntropyWithOptions(wordCount, g.options()...)
+}
+
+// Reconfigure replaces the generator's options with opts, as if it had
+// been created with them, so a long-lived Generator, e.g. in a server, can
+// follow a configuration change without being recreated. A seeded
+// generator keeps its seeded stream. The new options are validated first:
+// if they are invalid, Reconfigure returns the error and the generator
+// keeps its current options.
+func (g *Generator) Reconfigure(opts ...Option) error {
+	return g.update(func([]Option) []Option {
+		return append([]Option{}, opts...)
+	})
+}
+
+// SetLanguage switches the generator to lang, keeping its other options.
+// Unlike adding WithLanguage, it also replaces any WithWordlists,
+// WithWordlistSources or WithAlternatingLanguages lists, which would
+// otherwise take precedence. Returns an error, keeping the current
+// options, if lang is unsupported.
+func (g *Generator) SetLanguage(lang Language) error {
+	useLang := func(o *options) {
+		o.lang = lang
+		o.wordlists = nil
+		o.alternate = nil
+		o.sourceErr = nil
+	}
+	return g.update(func(current []Option) []Option {
+		return append(slices.Clip(current), useLang)
+	})
+}
+
+// options returns the generator's current options. The slice is never
+// modified, so it can be used after the lock is released.
+func (g *Generator) options() []Option {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.opts
+}
+
+// update makes next(current options) the generator's options if they are
+// valid. The lock is held throughout, so concurrent updates don't lose
+// each other's changes.
+func (g *Generator) update(next func(current []Option) []Option) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	opts := next(g.opts)
+	if err := newOptions(opts...).validate(); err != nil {
+		return err
+	}
+	g.opts = opts
+	return nil
+}
+
+// seededReader is a deterministic io.Reader over math/rand for
+// NewSeededGenerator. The mutex makes it safe for concurrent use, although
+// concurrent callers then see an unpredictable interleaving of the stream.
+type seededReader struct {
+	mu  sync.Mutex
+	rng *mrand.Rand
+}
+
+func (r *seededReader) Read(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := 0; i < len(p); i += 8 {
+		v := r.rng.Uint64()
+		for j := i; j < len(p) && j < i+8; j++ {
+			p[j] = byte(v)
+			v >>= 8
+		}
+	}
+	return len(p), nil
+}
@@ -0,0 +1,127 @@
+package diceware
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestNewGenerator(t *testing.T) {
+	gen := NewGenerator(WithSeparator("-"), WithLanguage(LanguageRomanian))
+	passphrase, err := gen.Generate(5)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := len(splitWords(passphrase, "-")); got != 5 {
+		t.Errorf("Generate() = %q, want 5 words", passphrase)
+	}
+	if got, want := gen.Entropy(5), EntropyForLanguage(5, LanguageRomanian); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %f, want %f", got, want)
+	}
+	if _, err := gen.Generate(0); err == nil {
+		t.Error("Generate(0) should return an error")
+	}
+}
+
+func TestNewSeededGenerator(t *testing.T) {
+	sequence := func(seed int64, opts ...Option) []string {
+		gen := NewSeededGenerator(seed, opts...)
+		var out []string
+		for i := 0; i < 5; i++ {
+			passphrase, err := gen.Generate(6)
+			if err != nil {
+				t.Fatalf("Generate() error = %v", err)
+			}
+			out = append(out, passphrase)
+		}
+		return out
+	}
+
+	opts := []Option{WithLanguage(LanguageMixed), WithSeparator(" "), WithNumberWord(2)}
+	a, b := sequence(42, opts...), sequence(42, opts...)
+	for i := range a {
+		if a[i] != b[i] {
+			t.Errorf("passphrase %d differs between runs with the same seed: %q vs %q", i, a[i], b[i])
+		}
+	}
+	if a[0] == a[1] {
+		t.Errorf("consecutive passphrases are identical: %q", a[0])
+	}
+
+	c := sequence(43, opts...)
+	if strings.Join(a, "|") == strings.Join(c, "|") {
+		t.Error("different seeds produced the same sequence")
+	}
+}
+
+func TestGeneratorReconfigure(t *testing.T) {
+	gen := NewGenerator(WithSeparator("-"))
+	if err := gen.Reconfigure(WithSeparator(" "), WithLanguage(LanguageRomanian)); err != nil {
+		t.Fatalf("Reconfigure() error = %v", err)
+	}
+	passphrase, err := gen.Generate(4)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if got := len(strings.Split(passphrase, " ")); got != 4 {
+		t.Errorf("Generate() = %q, want 4 words separated by spaces", passphrase)
+	}
+	if got, want := gen.Entropy(4), EntropyForLanguage(4, LanguageRomanian); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() = %f, want %f", got, want)
+	}
+
+	// Invalid options are rejected and the old ones kept
+	if err := gen.Reconfigure(WithWordlists()); err == nil {
+		t.Error("Reconfigure() with no wordlists should return an error")
+	}
+	if err := gen.SetLanguage(Language(99)); err == nil {
+		t.Error("SetLanguage(99) should return an error")
+	}
+	if got, want := gen.Entropy(4), EntropyForLanguage(4, LanguageRomanian); math.Abs(got-want) > 1e-9 {
+		t.Errorf("Entropy() after failed changes = %f, want %f", got, want)
+	}
+
+	// SetLanguage keeps the other options and replaces custom lists
+	custom, _ := NewWordlist("custom", map[string]string{"11111": "otter"})
+	gen = NewGenerator(WithWordlists(custom), WithSeparator("."))
+	if err := gen.SetLanguage(LanguageEnglish); err != nil {
+		t.Fatalf("SetLanguage() error = %v", err)
+	}
+	passphrase, err = gen.Generate(6)
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if strings.Contains(passphrase, "Otter.Otter") || len(splitWords(passphrase, ".")) != 6 {
+		t.Errorf("Generate() = %q, want 6 English words separated by dots", passphrase)
+	}
+}
+
+// TestGeneratorReconfigureConcurrent changes a Generator's language while
+// other goroutines generate with it; run with -race.
+func TestGeneratorReconfigureConcurrent(t *testing.T) {
+	gen := NewGenerator()
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				if _, err := gen.Generate(4); err != nil {
+					t.Errorf("Generate() error = %v", err)
+					return
+				}
+			}
+		}()
+	}
+	for j := 0; j < 50; j++ {
+		lang := LanguageEnglish
+		if j%2 == 1 {
+			lang = LanguageRomanian
+		}
+		if err := gen.SetLanguage(lang); err != nil {
+			t.Errorf("SetLanguage() error = %v", err)
+		}
+	}
+	wg.Wait()
+}
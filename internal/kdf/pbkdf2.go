@@ -0,0 +1,57 @@
+// Package kdf derives keys from passphrases for the CLI's --hash flag. It
+// implements PBKDF2 (RFC 8018) with the standard library's HMAC, as the
+// memory-hard KDFs (argon2, scrypt) and bcrypt aren't in the standard
+// library.
+package kdf
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"hash"
+)
+
+// PBKDF2 derives a key of keyLen bytes from password and salt with
+// iterations rounds of HMAC using h, as in RFC 8018 section 5.2.
+func PBKDF2(h func() hash.Hash, password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(h, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+
+	key := make([]byte, 0, blocks*size)
+	u := make([]byte, size)
+	t := make([]byte, size)
+	var counter [4]byte
+	for block := 1; block <= blocks; block++ {
+		// U1 = PRF(password, salt || INT(block))
+		binary.BigEndian.PutUint32(counter[:], uint32(block))
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(counter[:])
+		u = prf.Sum(u[:0])
+		copy(t, u)
+
+		// Ui = PRF(password, Ui-1), T = U1 ^ U2 ^ ... ^ Uc
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
+
+// PBKDF2SHA256 is PBKDF2 with HMAC-SHA256, formatted as a PHC string:
+// "$pbkdf2-sha256$i=<iterations>$<salt>$<key>", the salt and the 32-byte
+// key in unpadded standard base64.
+func PBKDF2SHA256(password, salt []byte, iterations int) string {
+	key := PBKDF2(sha256.New, password, salt, iterations, sha256.Size)
+	enc := base64.RawStdEncoding
+	return fmt.Sprintf("$pbkdf2-sha256$i=%d$%s$%s", iterations, enc.EncodeToString(salt), enc.EncodeToString(key))
+}
@@ -0,0 +1,44 @@
+package kdf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+// The PBKDF2-HMAC-SHA256 test vectors of RFC 7914 section 11.
+func TestPBKDF2(t *testing.T) {
+	tests := []struct {
+		password, salt string
+		iterations     int
+		want           string
+	}{
+		{"passwd", "salt", 1, "55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc" +
+			"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783"},
+		{"Password", "NaCl", 80000, "4ddcd8f60b98be21830cee5ef22701f9641a4418d04c0414aeff08876b34ab56" +
+			"a1d425a1225833549adb841b51c9b3176a272bdebba1d078478f62b397f33c8d"},
+	}
+	for _, tt := range tests {
+		got := PBKDF2(sha256.New, []byte(tt.password), []byte(tt.salt), tt.iterations, 64)
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("PBKDF2(%q, %q, %d) = %x, want %s", tt.password, tt.salt, tt.iterations, got, tt.want)
+		}
+		// A shorter key is a prefix of the longer one
+		if short := PBKDF2(sha256.New, []byte(tt.password), []byte(tt.salt), tt.iterations, 20); hex.EncodeToString(short) != tt.want[:40] {
+			t.Errorf("PBKDF2(..., 20) = %x, want %s", short, tt.want[:40])
+		}
+	}
+}
+
+func TestPBKDF2SHA256(t *testing.T) {
+	got := PBKDF2SHA256([]byte("passwd"), []byte("salt"), 1)
+	// The first 32 bytes of the RFC 7914 vector, in base64
+	want := "$pbkdf2-sha256$i=1$c2FsdA$VawEblbjCJ/sFpHCJUS2BflBhSFt3gRl5oudV8INrLw"
+	if got != want {
+		t.Errorf("PBKDF2SHA256() = %q, want %q", got, want)
+	}
+	if parts := strings.Split(got, "$"); len(parts) != 5 {
+		t.Errorf("PBKDF2SHA256() has %d fields, want 5", len(parts))
+	}
+}
@@ -0,0 +1,524 @@
+// Package qr encodes text as a QR code (ISO/IEC 18004) for display in a
+// terminal. It implements only what the CLI needs: byte mode, error
+// correction level M and the smallest version (1-40) that fits the data.
+package qr
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrTooLong is returned by Encode for data that doesn't fit in a version 40
+// symbol, 2331 bytes at level M.
+var ErrTooLong = errors.New("qr: data too long")
+
+// quietZone is the light border around the symbol, in modules, that
+// scanners need to find it.
+const quietZone = 4
+
+// Code is an encoded QR symbol.
+type Code struct {
+	// Size is the width and height of the symbol in modules, without the
+	// quiet zone.
+	Size int
+
+	dark []bool // row-major, Size × Size
+}
+
+// Dark reports whether the module at column x and row y is dark. Modules
+// outside the symbol are light.
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.dark[y*c.Size+x]
+}
+
+// String renders the code, quiet zone included, with Unicode half blocks,
+// two rows of modules per line. Light modules are drawn as blocks and dark
+// ones left blank, so it scans on the usual light-on-dark terminal; on a
+// dark-on-light terminal it shows inverted, which most phone scanners still
+// read.
+func (c *Code) String() string {
+	var b strings.Builder
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			top, bottom := !c.Dark(x, y), !c.Dark(x, y+1)
+			// The last line of an odd height has no bottom row
+			if y+1 >= c.Size+quietZone {
+				bottom = false
+			}
+			switch {
+			case top && bottom:
+				b.WriteRune('█')
+			case top:
+				b.WriteRune('▀')
+			case bottom:
+				b.WriteRune('▄')
+			default:
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Encode encodes data in byte mode at error correction level M, using the
+// smallest version it fits in and the mask with the lowest penalty.
+func Encode(data string) (*Code, error) {
+	version := 0
+	for v := 1; v <= 40; v++ {
+		if 4+countBits(v)+8*len(data) <= 8*dataCodewords(v) {
+			version = v
+			break
+		}
+	}
+	if version == 0 {
+		return nil, ErrTooLong
+	}
+
+	codewords := addECC(encodeData(data, version), version)
+
+	var best *Code
+	bestPenalty := 0
+	for mask := 0; mask < 8; mask++ {
+		c := build(codewords, version, mask)
+		if p := c.penalty(); best == nil || p < bestPenalty {
+			best, bestPenalty = c, p
+		}
+	}
+	return best, nil
+}
+
+// eccLevelM holds the number of error correction blocks and codewords per
+// block at level M, indexed by version.
+var eccLevelM = [41]struct{ blocks, ecc int }{
+	{},
+	{1, 10}, {1, 16}, {1, 26}, {2, 18}, {2, 24}, {4, 16}, {4, 18}, {4, 22}, {5, 22}, {5, 26},
+	{5, 30}, {8, 22}, {9, 22}, {9, 24}, {10, 24}, {10, 28}, {11, 28}, {13, 26}, {14, 26}, {16, 26},
+	{17, 26}, {17, 28}, {18, 28}, {20, 28}, {21, 28}, {23, 28}, {25, 28}, {26, 28}, {28, 28}, {29, 28},
+	{31, 28}, {33, 28}, {35, 28}, {37, 28}, {38, 28}, {40, 28}, {43, 28}, {45, 28}, {47, 28}, {49, 28},
+}
+
+// rawCodewords returns the number of codewords a symbol of the version
+// holds, data and error correction together.
+func rawCodewords(version int) int {
+	modules := (16*version+128)*version + 64
+	if version >= 2 {
+		align := version/7 + 2
+		modules -= (25*align-10)*align - 55
+		if version >= 7 {
+			modules -= 36 // version information
+		}
+	}
+	return modules / 8
+}
+
+// dataCodewords returns the number of data codewords of the version at
+// level M.
+func dataCodewords(version int) int {
+	e := eccLevelM[version]
+	return rawCodewords(version) - e.blocks*e.ecc
+}
+
+// countBits returns the width of the byte mode character count.
+func countBits(version int) int {
+	if version <= 9 {
+		return 8
+	}
+	return 16
+}
+
+// encodeData returns the data codewords: the byte mode segment, the
+// terminator and padding up to the capacity of the version.
+func encodeData(data string, version int) []byte {
+	var bits bitBuffer
+	bits.write(0b0100, 4) // byte mode
+	bits.write(len(data), countBits(version))
+	for i := 0; i < len(data); i++ {
+		bits.write(int(data[i]), 8)
+	}
+
+	capacity := 8 * dataCodewords(version)
+	bits.write(0, min(4, capacity-len(bits)))
+	bits.write(0, -len(bits)&7)
+	for pad := 0xEC; len(bits) < capacity; pad ^= 0xEC ^ 0x11 {
+		bits.write(pad, 8)
+	}
+	return bits.bytes()
+}
+
+// bitBuffer accumulates bits, most significant first.
+type bitBuffer []bool
+
+func (b *bitBuffer) write(v, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*b = append(*b, v>>i&1 == 1)
+	}
+}
+
+func (b bitBuffer) bytes() []byte {
+	out := make([]byte, len(b)/8)
+	for i, bit := range b {
+		if bit {
+			out[i/8] |= 0x80 >> (i % 8)
+		}
+	}
+	return out
+}
+
+// addECC splits the data codewords into blocks, computes each block's
+// Reed-Solomon codewords and interleaves the lot into the final sequence.
+func addECC(data []byte, version int) []byte {
+	e := eccLevelM[version]
+	raw := rawCodewords(version)
+	short := e.blocks - raw%e.blocks // blocks one data codeword shorter
+	shortLen := raw/e.blocks - e.ecc
+
+	divisor := rsDivisor(e.ecc)
+	blocks := make([][]byte, e.blocks)
+	eccs := make([][]byte, e.blocks)
+	for i := range blocks {
+		n := shortLen
+		if i >= short {
+			n++
+		}
+		blocks[i], data = data[:n], data[n:]
+		eccs[i] = rsRemainder(blocks[i], divisor)
+	}
+
+	out := make([]byte, 0, raw)
+	for i := 0; i <= shortLen; i++ {
+		for _, block := range blocks {
+			if i < len(block) {
+				out = append(out, block[i])
+			}
+		}
+	}
+	for i := 0; i < e.ecc; i++ {
+		for _, ecc := range eccs {
+			out = append(out, ecc[i])
+		}
+	}
+	return out
+}
+
+// gfMul multiplies in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMul(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = z<<1 ^ (z>>7)*0x11D
+		z ^= int(y>>i&1) * int(x)
+	}
+	return byte(z)
+}
+
+// rsDivisor returns the coefficients of the Reed-Solomon generator
+// polynomial of the degree, highest first, the leading 1 omitted.
+func rsDivisor(degree int) []byte {
+	poly := make([]byte, degree)
+	poly[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		// Multiply by (x - root)
+		for j := range poly {
+			poly[j] = gfMul(poly[j], root)
+			if j+1 < len(poly) {
+				poly[j] ^= poly[j+1]
+			}
+		}
+		root = gfMul(root, 2)
+	}
+	return poly
+}
+
+// rsRemainder returns the Reed-Solomon codewords of data for the divisor.
+func rsRemainder(data, divisor []byte) []byte {
+	rem := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ rem[0]
+		copy(rem, rem[1:])
+		rem[len(rem)-1] = 0
+		for i, d := range divisor {
+			rem[i] ^= gfMul(d, factor)
+		}
+	}
+	return rem
+}
+
+// symbol is a code under construction, recording which modules belong to
+// function patterns and can't hold data.
+type symbol struct {
+	*Code
+	function []bool
+}
+
+func (s *symbol) set(x, y int, dark bool) {
+	s.dark[y*s.Size+x] = dark
+	s.function[y*s.Size+x] = true
+}
+
+// build draws the symbol of the version holding codewords under the mask.
+func build(codewords []byte, version, mask int) *Code {
+	s := newSymbol(version, mask)
+	s.place(codewords)
+	for y := 0; y < s.Size; y++ {
+		for x := 0; x < s.Size; x++ {
+			if !s.function[y*s.Size+x] && masked(mask, x, y) {
+				s.dark[y*s.Size+x] = !s.dark[y*s.Size+x]
+			}
+		}
+	}
+	return s.Code
+}
+
+// newSymbol returns a symbol of the version with its function patterns
+// drawn and format information for the mask.
+func newSymbol(version, mask int) *symbol {
+	size := 4*version + 17
+	s := &symbol{
+		Code:     &Code{Size: size, dark: make([]bool, size*size)},
+		function: make([]bool, size*size),
+	}
+
+	for i := 0; i < size; i++ {
+		s.set(6, i, i%2 == 0)
+		s.set(i, 6, i%2 == 0)
+	}
+	s.finder(3, 3)
+	s.finder(size-4, 3)
+	s.finder(3, size-4)
+	align := alignmentPositions(version)
+	last := len(align) - 1
+	for i, x := range align {
+		for j, y := range align {
+			// Skip the three corners taken by finder patterns
+			if i == 0 && j == 0 || i == 0 && j == last || i == last && j == 0 {
+				continue
+			}
+			s.alignment(x, y)
+		}
+	}
+	s.formatInfo(mask)
+	s.versionInfo(version)
+	return s
+}
+
+// finder draws a finder pattern and its separator centered on x, y.
+func (s *symbol) finder(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			xx, yy := x+dx, y+dy
+			if xx < 0 || xx >= s.Size || yy < 0 || yy >= s.Size {
+				continue
+			}
+			d := max(abs(dx), abs(dy))
+			s.set(xx, yy, d != 2 && d != 4)
+		}
+	}
+}
+
+// alignment draws an alignment pattern centered on x, y.
+func (s *symbol) alignment(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			s.set(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPositions returns the row and column coordinates of the
+// alignment pattern centers of the version, ascending.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	n := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + n*2 + 1) / (n*2 - 2) * 2
+	}
+	pos := make([]int, n)
+	pos[0] = 6
+	for i, p := n-1, 4*version+10; i > 0; i, p = i-1, p-step {
+		pos[i] = p
+	}
+	return pos
+}
+
+// formatInfo draws both copies of the format information for level M and
+// the mask, plus the dark module.
+func (s *symbol) formatInfo(mask int) {
+	data := 0b00<<3 | mask // level M is 00
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = rem<<1 ^ (rem>>9)*0x537
+	}
+	bits := (data<<10 | rem) ^ 0x5412
+	bit := func(i int) bool { return bits>>i&1 == 1 }
+
+	for i := 0; i <= 5; i++ {
+		s.set(8, i, bit(i))
+	}
+	s.set(8, 7, bit(6))
+	s.set(8, 8, bit(7))
+	s.set(7, 8, bit(8))
+	for i := 9; i < 15; i++ {
+		s.set(14-i, 8, bit(i))
+	}
+
+	for i := 0; i < 8; i++ {
+		s.set(s.Size-1-i, 8, bit(i))
+	}
+	for i := 8; i < 15; i++ {
+		s.set(8, s.Size-15+i, bit(i))
+	}
+	s.set(8, s.Size-8, true)
+}
+
+// versionInfo draws both copies of the version information, present from
+// version 7 on.
+func (s *symbol) versionInfo(version int) {
+	if version < 7 {
+		return
+	}
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = rem<<1 ^ (rem>>11)*0x1F25
+	}
+	bits := version<<12 | rem
+	for i := 0; i < 18; i++ {
+		dark := bits>>i&1 == 1
+		a, b := s.Size-11+i%3, i/3
+		s.set(a, b, dark)
+		s.set(b, a, dark)
+	}
+}
+
+// place lays out the codewords in the data modules. Leftover modules stay
+// light.
+func (s *symbol) place(codewords []byte) {
+	i := 0
+	s.dataModules(func(x, y int) {
+		if i < len(codewords)*8 {
+			s.dark[y*s.Size+x] = codewords[i/8]>>(7-i%8)&1 == 1
+			i++
+		}
+	})
+}
+
+// dataModules calls fn with the modules that aren't part of a function
+// pattern, in placement order: two-module wide columns from the right,
+// zigzagging up and down.
+func (s *symbol) dataModules(fn func(x, y int)) {
+	for right := s.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5 // skip the vertical timing pattern
+		}
+		upward := (right+1)&2 == 0
+		for v := 0; v < s.Size; v++ {
+			y := v
+			if upward {
+				y = s.Size - 1 - v
+			}
+			for x := right; x >= right-1; x-- {
+				if !s.function[y*s.Size+x] {
+					fn(x, y)
+				}
+			}
+		}
+	}
+}
+
+// masked reports whether the mask pattern inverts the module at x, y.
+func masked(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// penalty scores the symbol by the four rules of the specification: runs of
+// same-colored modules, 2×2 blocks, finder-like patterns and an unbalanced
+// share of dark modules. Lower is easier to scan.
+func (c *Code) penalty() int {
+	p := 0
+	darkCount := 0
+	for i := 0; i < c.Size; i++ {
+		row := func(j int) bool { return c.Dark(j, i) }
+		col := func(j int) bool { return c.Dark(i, j) }
+		p += c.linePenalty(row) + c.linePenalty(col)
+	}
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			d := c.Dark(x, y)
+			if d {
+				darkCount++
+			}
+			if x+1 < c.Size && y+1 < c.Size && d == c.Dark(x+1, y) && d == c.Dark(x, y+1) && d == c.Dark(x+1, y+1) {
+				p += 3
+			}
+		}
+	}
+	total := c.Size * c.Size
+	p += abs(darkCount*20-total*10) / total * 10
+	return p
+}
+
+// finderLike are the 1:1:3:1:1 patterns with four light modules on one
+// side that rule 3 penalizes.
+var finderLike = [2][11]bool{
+	{true, false, true, true, true, false, true, false, false, false, false},
+	{false, false, false, false, true, false, true, true, true, false, true},
+}
+
+// linePenalty scores one row or column for rules 1 and 3.
+func (c *Code) linePenalty(at func(int) bool) int {
+	p := 0
+	run := 1
+	for j := 1; j <= c.Size; j++ {
+		if j < c.Size && at(j) == at(j-1) {
+			run++
+			continue
+		}
+		if run >= 5 {
+			p += run - 2
+		}
+		run = 1
+	}
+
+	for j := 0; j+11 <= c.Size; j++ {
+		for _, pattern := range finderLike {
+			match := true
+			for k, dark := range pattern {
+				if at(j+k) != dark {
+					match = false
+					break
+				}
+			}
+			if match {
+				p += 40
+			}
+		}
+	}
+	return p
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,273 @@
+package qr
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// decode reads c back the way a scanner would, minus the image processing:
+// it checks the function patterns and error correction codewords, and
+// returns the byte mode payload.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+	version := (c.Size - 17) / 4
+
+	// Format information, first copy
+	format := 0
+	for i := 0; i <= 5; i++ {
+		format |= bit(c.Dark(8, i)) << i
+	}
+	format |= bit(c.Dark(8, 7))<<6 | bit(c.Dark(8, 8))<<7 | bit(c.Dark(7, 8))<<8
+	for i := 9; i < 15; i++ {
+		format |= bit(c.Dark(14-i, 8)) << i
+	}
+	format ^= 0x5412
+	if level := format >> 13; level != 0b00 {
+		t.Fatalf("format information has level %02b, want M (00)", level)
+	}
+	mask := format >> 10 & 7
+
+	s := newSymbol(version, mask)
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if s.function[y*c.Size+x] && s.Dark(x, y) != c.Dark(x, y) {
+				t.Fatalf("function module %d,%d differs", x, y)
+			}
+		}
+	}
+
+	var bits bitBuffer
+	s.dataModules(func(x, y int) {
+		bits = append(bits, c.Dark(x, y) != masked(mask, x, y))
+	})
+	codewords := bits.bytes()[:rawCodewords(version)]
+
+	// Undo the interleaving
+	e := eccLevelM[version]
+	short := e.blocks - rawCodewords(version)%e.blocks
+	shortLen := rawCodewords(version)/e.blocks - e.ecc
+	blocks := make([][]byte, e.blocks)
+	for i := 0; i <= shortLen; i++ {
+		for b := range blocks {
+			if i < shortLen || b >= short {
+				blocks[b] = append(blocks[b], codewords[0])
+				codewords = codewords[1:]
+			}
+		}
+	}
+	var data []byte
+	for b, block := range blocks {
+		ecc := rsRemainder(block, rsDivisor(e.ecc))
+		for i, want := range ecc {
+			if codewords[i*e.blocks+b] != want {
+				t.Fatalf("block %d: error correction codeword %d doesn't match", b, i)
+			}
+		}
+		data = append(data, block...)
+	}
+
+	pos := 0
+	read := func(n int) int {
+		v := 0
+		for ; n > 0; n-- {
+			v = v<<1 | int(data[pos/8]>>(7-pos%8)&1)
+			pos++
+		}
+		return v
+	}
+	if mode := read(4); mode != 0b0100 {
+		t.Fatalf("mode = %04b, want byte mode", mode)
+	}
+	payload := make([]byte, read(countBits(version)))
+	for i := range payload {
+		payload[i] = byte(read(8))
+	}
+	return string(payload)
+}
+
+func bit(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		data    string
+		version int
+	}{
+		{"", 1},
+		{"CorrectHorseBattery", 2},
+		{"Oppressor-Reroute-Moonstone-Disown-Kinetic-Sprang", 4},
+		{"mămăligă țuică ștrudel", 3},                  // 28 bytes of UTF-8
+		{strings.Repeat("Passphrase", 21) + "!!!", 10}, // 16-bit count
+		{strings.Repeat("x", 2331), 40},
+	}
+
+	for _, tt := range tests {
+		c, err := Encode(tt.data)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes) error = %v", len(tt.data), err)
+		}
+		if got := (c.Size - 17) / 4; got != tt.version {
+			t.Errorf("Encode(%d bytes) has version %d, want %d", len(tt.data), got, tt.version)
+		}
+		if got := decode(t, c); got != tt.data {
+			t.Errorf("decoded %q, want %q", got, tt.data)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("x", 2332)); !errors.Is(err, ErrTooLong) {
+		t.Errorf("Encode(2332 bytes) error = %v, want ErrTooLong", err)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	// Byte mode capacities at level M from the specification
+	for version, want := range map[int]int{1: 14, 2: 26, 7: 122, 10: 213, 27: 1125, 40: 2331} {
+		got := (8*dataCodewords(version) - 4 - countBits(version)) / 8
+		if got != want {
+			t.Errorf("version %d holds %d bytes, want %d", version, got, want)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	c, err := Encode("diceware")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.String(), "\n"), "\n")
+	width := c.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("got %d lines, want %d", len(lines), (width+1)/2)
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Errorf("line %d is %d wide, want %d", i, n, width)
+		}
+	}
+	// The quiet zone is light, drawn as full blocks
+	if !strings.HasPrefix(lines[0], strings.Repeat("█", width)) {
+		t.Errorf("first line %q should be all quiet zone", lines[0])
+	}
+	// The top left finder pattern: a dark row of 7 over dark, 5 light, dark
+	if !strings.HasPrefix(lines[2], "████ ▄▄▄▄▄ ") {
+		t.Errorf("line %q should start with the finder pattern", lines[2])
+	}
+}
+
+// readBits reads the modules at positions of c as a string of 0s and 1s,
+// 1 for dark.
+func readBits(c *Code, positions [][2]int) string {
+	var b strings.Builder
+	for _, p := range positions {
+		b.WriteByte(byte('0' + bit(c.Dark(p[0], p[1]))))
+	}
+	return b.String()
+}
+
+func TestFormatInfo(t *testing.T) {
+	// Level M format information from the specification, most significant
+	// bit first, by mask
+	want := [8]string{
+		"101010000010010", "101000100100101", "101111001111100", "101101101001011",
+		"100010111111001", "100000011001110", "100111110010111", "100101010100000",
+	}
+	for mask, bits := range want {
+		c := newSymbol(1, mask).Code
+		// The copy around the top left finder runs along row 8 and up
+		// column 8, skipping the timing patterns
+		first := [][2]int{{0, 8}, {1, 8}, {2, 8}, {3, 8}, {4, 8}, {5, 8}, {7, 8}, {8, 8},
+			{8, 7}, {8, 5}, {8, 4}, {8, 3}, {8, 2}, {8, 1}, {8, 0}}
+		// The other runs up column 8 at the bottom, then along row 8 at the
+		// right
+		var second [][2]int
+		for i := 0; i < 7; i++ {
+			second = append(second, [2]int{8, c.Size - 1 - i})
+		}
+		for i := 8; i > 0; i-- {
+			second = append(second, [2]int{c.Size - i, 8})
+		}
+		if got := readBits(c, first); got != bits {
+			t.Errorf("mask %d: format information %s, want %s", mask, got, bits)
+		}
+		if got := readBits(c, second); got != bits {
+			t.Errorf("mask %d: second format information %s, want %s", mask, got, bits)
+		}
+	}
+}
+
+func TestVersionInfo(t *testing.T) {
+	// Version information from the specification, most significant bit
+	// first
+	for version, bits := range map[int]string{
+		7:  "000111110010010100",
+		8:  "001000010110111100",
+		40: "101000110001101001",
+	} {
+		c := newSymbol(version, 0).Code
+		// Bit i is at column i/3 of the 6×3 block above the bottom left
+		// finder, row i%3, and transposed above the top right one
+		var bottomLeft, topRight [][2]int
+		for i := 17; i >= 0; i-- {
+			bottomLeft = append(bottomLeft, [2]int{i / 3, c.Size - 11 + i%3})
+			topRight = append(topRight, [2]int{c.Size - 11 + i%3, i / 3})
+		}
+		if got := readBits(c, bottomLeft); got != bits {
+			t.Errorf("version %d: version information %s, want %s", version, got, bits)
+		}
+		if got := readBits(c, topRight); got != bits {
+			t.Errorf("version %d: second version information %s, want %s", version, got, bits)
+		}
+	}
+}
+
+func TestReferenceSymbol(t *testing.T) {
+	// "Colt-Default-Arousal" at version 2-M with mask 0, from an
+	// independent encoder; # is dark
+	want := []string{
+		"#######....##..#..#######",
+		"#.....#.#.###..##.#.....#",
+		"#.###.#..##.......#.###.#",
+		"#.###.#..##...###.#.###.#",
+		"#.###.#.#####.##..#.###.#",
+		"#.....#....#.#.##.#.....#",
+		"#######.#.#.#.#.#.#######",
+		"..........#.###.#........",
+		"#.#.#.#.....##.##...#..#.",
+		"#.#..#....##....#.#...###",
+		"#.##.##.#.......#####.###",
+		"#.#.#..#.###.#.....#....#",
+		"#.#.########..#..##..#.#.",
+		"..##.#..#..#....###....##",
+		"#..#.##.##...#...#....###",
+		".#..#......#...#.#...#..#",
+		"#..##.#.#...#...#####...#",
+		"........##.###.##...#.##.",
+		"#######...###.#.#.#.#####",
+		"#.....#..#..###.#...#..#.",
+		"#.###.#.####..#.#####.###",
+		"#.###.#..###..#....##....",
+		"#.###.#.###..#.#.#.##.#.#",
+		"#.....#..#.#...##......#.",
+		"#######.##..#...#.###..##",
+	}
+	const data = "Colt-Default-Arousal"
+	c := build(addECC(encodeData(data, 2), 2), 2, 0)
+	for y, row := range want {
+		var got strings.Builder
+		for x := 0; x < c.Size; x++ {
+			if c.Dark(x, y) {
+				got.WriteByte('#')
+			} else {
+				got.WriteByte('.')
+			}
+		}
+		if got.String() != row {
+			t.Errorf("row %d = %s, want %s", y, got.String(), row)
+		}
+	}
+}
@@ -0,0 +1,202 @@
+package diceware
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// birthdayApproxRatio is how small users/N must be for CollisionProbability
+// to use the closed form of the birthday bound instead of summing a term
+// per user.
+const birthdayApproxRatio = 1e-6
+
+// AttackKeyspace returns the exact number of distinct passphrases of
+// wordCount words in lang, i.e. how many candidates an attacker who knows
+// the wordlist, the word count and the capitalization must try in the worst
+// case. Some compliance documents ask for this raw figure rather than the
+// log2 entropy; for 6 English words it is 7776^6 ≈ 2.21×10^23.
+//
+// For LanguageMixed the words of both lists are pooled, roughly doubling
+// the base. Words that appear in both lists (e.g. "abator") produce the
+// same passphrase whichever list they came from, so they are counted once:
+// the base is 15,030 rather than the 15,311 usable rolls behind
+// EntropyForLanguage, which counts each roll as an outcome.
+//
+// The keyspace is computed with math/big and never overflows. It is 0 if
+// wordCount is less than 1 or lang is unsupported.
+func AttackKeyspace(wordCount int, lang Language) *big.Int {
+	if wordCount < 1 {
+		return new(big.Int)
+	}
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return new(big.Int)
+	}
+	base := big.NewInt(int64(distinctWords(lists)))
+	return base.Exp(base, big.NewInt(int64(wordCount)), nil)
+}
+
+// distinctWords returns the number of distinct usable words across lists,
+// ignoring case.
+func distinctWords(lists []*Wordlist) int {
+	if len(lists) == 1 {
+		return lists[0].Size()
+	}
+	seen := make(map[string]bool)
+	for _, wl := range lists {
+		for _, word := range wl.words {
+			if word != "" && wl.accepts(word) {
+				seen[strings.ToLower(word)] = true
+			}
+		}
+	}
+	return len(seen)
+}
+
+// CollisionProbability returns the probability that at least two of users
+// independently generated passphrases of wordCount words in lang are
+// identical - the birthday problem - so applications provisioning many
+// accounts can check that duplicates are negligible:
+//
+//	p := diceware.CollisionProbability(100_000, 6, diceware.LanguageEnglish)
+//	// p ≈ 2.3e-14
+//
+// The product of (1 - i/N) over all users is summed as logarithms, so it
+// neither overflows for huge keyspaces nor loses tiny probabilities to
+// rounding. For LanguageMixed, words both lists share are twice as likely
+// to come up, which slightly raises the odds; that is accounted for.
+// Returns 0 for fewer than 2 users, a word count below 1 or an unsupported
+// language.
+func CollisionProbability(users, wordCount int, lang Language) float64 {
+	if users < 2 || wordCount < 1 {
+		return 0
+	}
+	lists, weights, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return 0
+	}
+	// The chance that two passphrases match is match^wordCount; N is its
+	// inverse, the number of equally likely passphrases with the same odds
+	logN := -float64(wordCount) * math.Log(matchProbability(lists, weights))
+	return birthday(users, logN)
+}
+
+// birthday returns the probability that k draws from N equally likely
+// values are not all distinct, given ln N.
+func birthday(k int, logN float64) float64 {
+	invN := math.Exp(-logN)
+	if float64(k-1)*invN < birthdayApproxRatio {
+		// Σ ln(1 - i/N) ≈ -Σ i/N = -k(k-1)/2N, accurate to (k/N)² here
+		return -math.Expm1(-float64(k) * float64(k-1) / 2 * invN)
+	}
+
+	logNoCollision := 0.0
+	for i := 1; i < k; i++ {
+		x := float64(i) * invN
+		if x >= 1 {
+			return 1
+		}
+		logNoCollision += math.Log1p(-x)
+	}
+	return -math.Expm1(logNoCollision)
+}
+
+// matchProbability returns the probability that two words drawn from lists
+// with the given weights (nil meaning uniform) are the same word, ignoring
+// case.
+func matchProbability(lists []*Wordlist, weights []float64) float64 {
+	if len(lists) == 1 {
+		return 1 / float64(lists[0].Size())
+	}
+
+	accept := acceptRate(lists, weights)
+	p := make(map[string]float64)
+	for i, wl := range lists {
+		w := 1 / float64(len(lists))
+		if weights != nil {
+			w = weights[i]
+		}
+		// Each usable roll of list i comes up with this probability, see
+		// options.bitsPerWord
+		for j, word := range wl.words {
+			if word != "" && wl.accepts(word) {
+				p[strings.ToLower(word)] += w * wl.entryProb(j) / accept
+			}
+		}
+	}
+
+	match := 0.0
+	for _, pw := range p {
+		match += pw * pw
+	}
+	return match
+}
+
+// GenerateUniqueBatch generates count passphrases of wordCount words in
+// lang that are all distinct, e.g. one per student of a class: a
+// passphrase identical to one already in the batch is discarded and
+// generated again. This is uniqueness across passphrases, unlike
+// WithUniqueWords which avoids repeated words within one.
+//
+// With realistic word counts collisions are vanishingly rare (see
+// CollisionProbability) and the check costs nothing; it matters for small
+// keyspaces. Returns an error if count exceeds AttackKeyspace, as there
+// aren't that many distinct passphrases, or for the same reasons as
+// GenerateWithLanguage.
+func GenerateUniqueBatch(count, wordCount int, lang Language) ([]string, error) {
+	if count < 0 {
+		return nil, fmt.Errorf("passphrase count must not be negative, got %d", count)
+	}
+	if wordCount < 1 {
+		return nil, invalidWordCount(wordCount)
+	}
+	keyspace := AttackKeyspace(wordCount, lang)
+	if keyspace.Sign() == 0 {
+		return nil, unsupportedLanguage(lang)
+	}
+	if keyspace.Cmp(big.NewInt(int64(count))) < 0 {
+		return nil, fmt.Errorf("can't generate %d distinct passphrases of %d words: there are only %s", count, wordCount, keyspace)
+	}
+
+	o := newOptions(WithLanguage(lang))
+	batch := make([]string, 0, count)
+	seen := make(map[string]bool, count)
+	for len(batch) < count {
+		maxAttempts := batchAttempts(keyspace, len(batch))
+
+		var passphrase string
+		for attempt := 0; ; attempt++ {
+			if attempt == maxAttempts {
+				return nil, fmt.Errorf("%w: no new passphrase after %d attempts: %w", ErrWordNotFound, maxAttempts, ErrMaxAttempts)
+			}
+			p, _, _, err := generate(wordCount, o)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[p] {
+				passphrase = p
+				break
+			}
+		}
+		seen[passphrase] = true
+		batch = append(batch, passphrase)
+	}
+	return batch, nil
+}
+
+// batchAttempts returns how many times GenerateUniqueBatch draws for a new
+// passphrase once used of keyspace are taken: like drawDistinct, 100 plus
+// 40 × keyspace / free, which grows as the keyspace fills up. It is
+// computed in big.Int, as keyspaces easily pass int64, and clamped to
+// math.MaxInt32.
+func batchAttempts(keyspace *big.Int, used int) int {
+	free := new(big.Int).Sub(keyspace, big.NewInt(int64(used)))
+	extra := new(big.Int).Mul(keyspace, big.NewInt(40))
+	extra.Quo(extra, free)
+	if !extra.IsInt64() || extra.Int64() > math.MaxInt32-100 {
+		return math.MaxInt32
+	}
+	return 100 + int(extra.Int64())
+}
@@ -0,0 +1,144 @@
+package diceware
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func TestAttackKeyspace(t *testing.T) {
+	want, _ := new(big.Int).SetString("221073919720733357899776", 10) // 7776^6
+	if got := AttackKeyspace(6, LanguageEnglish); got.Cmp(want) != 0 {
+		t.Errorf("AttackKeyspace(6, English) = %v, want %v", got, want)
+	}
+
+	// Consistent with the entropy for single lists
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageReinhold} {
+		bits, _ := new(big.Float).SetInt(AttackKeyspace(4, lang)).Float64()
+		if got := math.Log2(bits); math.Abs(got-EntropyForLanguage(4, lang)) > 1e-9 {
+			t.Errorf("log2(AttackKeyspace(4, %v)) = %f, want %f", lang, got, EntropyForLanguage(4, lang))
+		}
+	}
+
+	// Mixed pools both lists, counting shared words once
+	base := AttackKeyspace(1, LanguageMixed).Int64()
+	if size := int64(WordlistSizeByLanguage(LanguageMixed)); base >= size || base <= size*9/10 {
+		t.Errorf("AttackKeyspace(1, Mixed) = %d, want a little less than %d", base, size)
+	}
+	if got, want := AttackKeyspace(3, LanguageMixed), new(big.Int).Exp(big.NewInt(base), big.NewInt(3), nil); got.Cmp(want) != 0 {
+		t.Errorf("AttackKeyspace(3, Mixed) = %v, want %v", got, want)
+	}
+
+	if AttackKeyspace(0, LanguageEnglish).Sign() != 0 || AttackKeyspace(4, Language(99)).Sign() != 0 {
+		t.Error("AttackKeyspace() should be 0 for invalid input")
+	}
+}
+
+func TestCollisionProbability(t *testing.T) {
+	// Exact birthday figures for a small keyspace: 23 people, 365 days
+	if got := birthday(23, math.Log(365)); math.Abs(got-0.5073) > 1e-4 {
+		t.Errorf("birthday(23, 365) = %f, want 0.5073", got)
+	}
+	if got := birthday(366, math.Log(365)); got != 1 {
+		t.Errorf("birthday(366, 365) = %f, want 1", got)
+	}
+
+	// 70 users with 4 English words: ~70·69/2 / 7776^4
+	want := 70.0 * 69 / 2 / math.Pow(7776, 4)
+	if got := CollisionProbability(70, 4, LanguageEnglish); math.Abs(got-want)/want > 1e-6 {
+		t.Errorf("CollisionProbability(70, 4, English) = %g, want %g", got, want)
+	}
+
+	// Tiny probabilities don't round to 0, huge keyspaces don't overflow
+	if got := CollisionProbability(1000, 20, LanguageEnglish); got <= 0 || got > 1e-70 {
+		t.Errorf("CollisionProbability(1000, 20, English) = %g, want a tiny positive number", got)
+	}
+	if got := CollisionProbability(1000, 100, LanguageMixed); got != 0 {
+		t.Errorf("CollisionProbability(1000, 100, Mixed) = %g, want 0 (below float64 range)", got)
+	}
+
+	// Certain collisions with more users than passphrases
+	if got := CollisionProbability(10000, 1, LanguageEnglish); got != 1 {
+		t.Errorf("CollisionProbability(10000, 1, English) = %g, want 1", got)
+	}
+
+	// Shared words make Mixed a little more collision-prone than a
+	// uniform pick among all its usable rolls
+	uniform := birthday(100, 2*math.Log(float64(WordlistSizeByLanguage(LanguageMixed))))
+	if got := CollisionProbability(100, 2, LanguageMixed); got <= uniform {
+		t.Errorf("CollisionProbability(100, 2, Mixed) = %g, want more than %g", got, uniform)
+	}
+
+	if CollisionProbability(1, 4, LanguageEnglish) != 0 || CollisionProbability(10, 0, LanguageEnglish) != 0 ||
+		CollisionProbability(10, 4, Language(99)) != 0 {
+		t.Error("CollisionProbability() should be 0 for invalid input")
+	}
+}
+
+func TestGenerateUniqueBatch(t *testing.T) {
+	batch, err := GenerateUniqueBatch(70, 4, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GenerateUniqueBatch() error = %v", err)
+	}
+	if len(batch) != 70 {
+		t.Fatalf("got %d passphrases, want 70", len(batch))
+	}
+
+	// Exhausting the keyspace yields every single-word passphrase once
+	batch, err = GenerateUniqueBatch(7776, 1, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GenerateUniqueBatch() error = %v", err)
+	}
+	seen := make(map[string]bool)
+	for _, p := range batch {
+		if seen[p] {
+			t.Fatalf("passphrase %q generated twice", p)
+		}
+		seen[p] = true
+	}
+	if len(seen) != 7776 {
+		t.Errorf("got %d distinct passphrases, want 7776", len(seen))
+	}
+
+	if _, err := GenerateUniqueBatch(7777, 1, LanguageEnglish); err == nil || !strings.Contains(err.Error(), "only 7776") {
+		t.Errorf("GenerateUniqueBatch() beyond the keyspace error = %v, want one naming its size", err)
+	}
+	if batch, err := GenerateUniqueBatch(0, 6, LanguageEnglish); err != nil || len(batch) != 0 {
+		t.Errorf("GenerateUniqueBatch(0) = %q, %v, want an empty batch", batch, err)
+	}
+	if _, err := GenerateUniqueBatch(-1, 6, LanguageEnglish); err == nil {
+		t.Error("a negative count should return an error")
+	}
+	if _, err := GenerateUniqueBatch(2, 0, LanguageEnglish); !errors.Is(err, ErrInvalidWordCount) {
+		t.Errorf("GenerateUniqueBatch() with 0 words error = %v, want ErrInvalidWordCount", err)
+	}
+	if _, err := GenerateUniqueBatch(2, 6, Language(99)); !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("GenerateUniqueBatch() with an unsupported language error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
+
+func TestBatchAttempts(t *testing.T) {
+	// 40 × keyspace overflows int64 from about 2.3e17
+	big18, _ := new(big.Int).SetString("1000000000000000000", 10)
+	tests := []struct {
+		keyspace *big.Int
+		used     int
+		want     int
+	}{
+		{big.NewInt(10), 0, 140},
+		{big.NewInt(10), 9, 500},
+		{big18, 5, 140},
+		{AttackKeyspace(6, LanguageEnglish), 1000, 140},
+	}
+	for _, tt := range tests {
+		if got := batchAttempts(tt.keyspace, tt.used); got != tt.want {
+			t.Errorf("batchAttempts(%s, %d) = %d, want %d", tt.keyspace, tt.used, got, tt.want)
+		}
+	}
+
+	if batch, err := GenerateUniqueBatch(3, 5, LanguageBIP39English); err != nil || len(batch) != 3 {
+		t.Errorf("GenerateUniqueBatch(3, 5 BIP39 words) = %v, %v", batch, err)
+	}
+}
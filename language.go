@@ -0,0 +1,82 @@
+package diceware
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// LanguageInfo describes a language for pickers and command line flags.
+type LanguageInfo struct {
+	// Language is the value to pass to WithLanguage and friends.
+	Language Language
+	// Code is a short identifier, e.g. "ro", accepted by ParseLanguage.
+	Code string
+	// Name is a human-readable name, e.g. "Romanian".
+	Name string
+}
+
+// builtinLanguages is the display table for the built-in languages, in
+// order. aliases are extra names ParseLanguage accepts besides the code.
+// Adding a language here is enough for the CLI and ListLanguages.
+var builtinLanguages = []struct {
+	info    LanguageInfo
+	aliases []string
+}{
+	{LanguageInfo{LanguageEnglish, "en", "English"}, []string{"english"}},
+	{LanguageInfo{LanguageRomanian, "ro", "Romanian"}, []string{"romanian"}},
+	{LanguageInfo{LanguageMixed, "mixed", "Mixed (English + Romanian)"}, []string{"mix"}},
+	{LanguageInfo{LanguageReinhold, "reinhold", "Reinhold original"}, []string{"original"}},
+	{LanguageInfo{LanguageBIP39English, "bip39", "BIP39 English"}, []string{"bip39-en"}},
+}
+
+// ListLanguages returns every supported language: the built-in ones first,
+// then those added with RegisterLanguage, in registration order. A
+// registered language's code is its lowercased name and its name is the
+// one it was registered with.
+func ListLanguages() []LanguageInfo {
+	infos := make([]LanguageInfo, 0, len(builtinLanguages))
+	for _, b := range builtinLanguages {
+		infos = append(infos, b.info)
+	}
+
+	wordlistsMu.RLock()
+	var registered []LanguageInfo
+	for lang, wl := range wordlists {
+		registered = append(registered, LanguageInfo{lang, strings.ToLower(wl.name), wl.name})
+	}
+	wordlistsMu.RUnlock()
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Language < registered[j].Language
+	})
+	return append(infos, registered...)
+}
+
+// ParseLanguage returns the language with the given code or name, ignoring
+// case: "en" or "english", "ro" or "romanian", "mixed" or "mix",
+// "reinhold" or "original", "bip39" or "bip39-en", or the name of a
+// registered language. Returns an ErrUnsupportedLanguage error listing the
+// valid codes otherwise.
+func ParseLanguage(code string) (LanguageInfo, error) {
+	for _, b := range builtinLanguages {
+		if strings.EqualFold(b.info.Code, code) {
+			return b.info, nil
+		}
+		for _, alias := range b.aliases {
+			if strings.EqualFold(alias, code) {
+				return b.info, nil
+			}
+		}
+	}
+
+	infos := ListLanguages()
+	codes := make([]string, len(infos))
+	for i, info := range infos {
+		if strings.EqualFold(info.Code, code) {
+			return info, nil
+		}
+		codes[i] = info.Code
+	}
+	return LanguageInfo{}, fmt.Errorf("%w %q (use %s)", ErrUnsupportedLanguage, code, strings.Join(codes, ", "))
+}
@@ -0,0 +1,62 @@
+package diceware
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestListLanguages(t *testing.T) {
+	infos := ListLanguages()
+	if len(infos) < int(numBuiltinLanguages) {
+		t.Fatalf("ListLanguages() returned %d languages, want at least %d", len(infos), numBuiltinLanguages)
+	}
+	for i, info := range infos[:numBuiltinLanguages] {
+		if info.Language != Language(i) {
+			t.Errorf("ListLanguages()[%d].Language = %v, want %v", i, info.Language, Language(i))
+		}
+		if info.Code == "" || info.Name == "" {
+			t.Errorf("ListLanguages()[%d] = %+v, want a code and a name", i, info)
+		}
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := []struct {
+		code string
+		want Language
+	}{
+		{"en", LanguageEnglish},
+		{"English", LanguageEnglish},
+		{"RO", LanguageRomanian},
+		{"mix", LanguageMixed},
+		{"original", LanguageReinhold},
+	}
+	for _, tt := range tests {
+		info, err := ParseLanguage(tt.code)
+		if err != nil || info.Language != tt.want {
+			t.Errorf("ParseLanguage(%q) = %v, %v, want %v", tt.code, info.Language, err, tt.want)
+		}
+	}
+
+	_, err := ParseLanguage("xx")
+	if !errors.Is(err, ErrUnsupportedLanguage) {
+		t.Errorf("ParseLanguage(\"xx\") error = %v, want ErrUnsupportedLanguage", err)
+	}
+}
+
+func TestParseLanguageRegistered(t *testing.T) {
+	lang, err := RegisterLanguage("Language Test", strings.NewReader("11111 alpha\n"))
+	if err != nil {
+		t.Fatalf("RegisterLanguage() error = %v", err)
+	}
+
+	info, err := ParseLanguage("language test")
+	if err != nil || info.Language != lang || info.Name != "Language Test" {
+		t.Errorf("ParseLanguage() = %+v, %v, want the registered language %v", info, err, lang)
+	}
+	infos := ListLanguages()
+	if last := infos[len(infos)-1]; last.Language != lang {
+		t.Errorf("ListLanguages() ends with %+v, want the registered language %v", last, lang)
+	}
+}
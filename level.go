@@ -0,0 +1,98 @@
+package diceware
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SecurityLevel is a named passphrase strength preset, so callers don't
+// need to know how many words give how many bits of entropy.
+type SecurityLevel int
+
+const (
+	// SecurityLow is for low-value accounts: at least 50 bits, 4 English
+	// words (~52 bits).
+	SecurityLow SecurityLevel = iota
+	// SecurityMedium is recommended for most accounts: at least 75 bits, 6
+	// English words (~78 bits).
+	SecurityMedium
+	// SecurityHigh is for high security accounts: at least 100 bits, 8
+	// English words (~103 bits).
+	SecurityHigh
+	// SecurityParanoid is for cryptocurrency wallets and master passwords:
+	// at least 150 bits, 12 English words (~155 bits).
+	SecurityParanoid
+)
+
+// securityLevels is the recommendation table behind the presets: the
+// minimum entropy each level guarantees and its name for String and
+// ParseSecurityLevel.
+var securityLevels = []struct {
+	level SecurityLevel
+	name  string
+	bits  float64
+}{
+	{SecurityLow, "low", 50},
+	{SecurityMedium, "medium", 75},
+	{SecurityHigh, "high", 100},
+	{SecurityParanoid, "paranoid", 150},
+}
+
+// String returns the level's name, e.g. "high".
+func (l SecurityLevel) String() string {
+	for _, s := range securityLevels {
+		if s.level == l {
+			return s.name
+		}
+	}
+	return fmt.Sprintf("SecurityLevel(%d)", int(l))
+}
+
+// MinEntropy returns the minimum bits of entropy a passphrase generated for
+// the level has, or 0 for an unknown level.
+func (l SecurityLevel) MinEntropy() float64 {
+	for _, s := range securityLevels {
+		if s.level == l {
+			return s.bits
+		}
+	}
+	return 0
+}
+
+// ParseSecurityLevel returns the level with the given name ("low",
+// "medium", "high" or "paranoid"), ignoring case.
+func ParseSecurityLevel(name string) (SecurityLevel, error) {
+	for _, s := range securityLevels {
+		if strings.EqualFold(s.name, name) {
+			return s.level, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown security level %q (use low, medium, high or paranoid)", name)
+}
+
+// WordCountForLevel returns the smallest number of words in lang that
+// reaches the level's minimum entropy. Languages with more words per list
+// (e.g. LanguageMixed) or fewer (small registered lists) get fewer or more
+// words than the English counts in the SecurityLevel docs.
+func WordCountForLevel(level SecurityLevel, lang Language) (int, error) {
+	bits := level.MinEntropy()
+	if bits == 0 {
+		return 0, fmt.Errorf("unknown security level: %v", level)
+	}
+	perWord := EntropyForLanguage(1, lang)
+	if perWord == 0 {
+		return 0, unsupportedLanguage(lang)
+	}
+	return int(math.Ceil(bits / perWord)), nil
+}
+
+// GenerateForLevel creates a passphrase in lang with enough words for the
+// security level, see WordCountForLevel.
+func GenerateForLevel(level SecurityLevel, lang Language) (string, error) {
+	wordCount, err := WordCountForLevel(level, lang)
+	if err != nil {
+		return "", err
+	}
+	return GenerateWithLanguage(wordCount, lang)
+}
@@ -0,0 +1,67 @@
+package diceware
+
+import "testing"
+
+func TestWordCountForLevel(t *testing.T) {
+	tests := []struct {
+		level SecurityLevel
+		lang  Language
+		want  int
+	}{
+		{SecurityLow, LanguageEnglish, 4},
+		{SecurityMedium, LanguageEnglish, 6},
+		{SecurityHigh, LanguageEnglish, 8},
+		{SecurityParanoid, LanguageEnglish, 12},
+		{SecurityMedium, LanguageRomanian, 6},
+		{SecurityParanoid, LanguageMixed, 11},
+	}
+
+	for _, tt := range tests {
+		got, err := WordCountForLevel(tt.level, tt.lang)
+		if err != nil {
+			t.Errorf("WordCountForLevel(%v, %v) error = %v", tt.level, tt.lang, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("WordCountForLevel(%v, %v) = %d, want %d", tt.level, tt.lang, got, tt.want)
+		}
+		if bits := EntropyForLanguage(got, tt.lang); bits < tt.level.MinEntropy() {
+			t.Errorf("%d words give %.1f bits, below the %v minimum of %.0f", got, bits, tt.level, tt.level.MinEntropy())
+		}
+	}
+
+	if _, err := WordCountForLevel(SecurityLevel(99), LanguageEnglish); err == nil {
+		t.Error("WordCountForLevel() with an unknown level should return an error")
+	}
+	if _, err := WordCountForLevel(SecurityHigh, Language(99)); err == nil {
+		t.Error("WordCountForLevel() with an unsupported language should return an error")
+	}
+}
+
+func TestGenerateForLevel(t *testing.T) {
+	passphrase, err := GenerateForLevel(SecurityHigh, LanguageEnglish)
+	if err != nil {
+		t.Fatalf("GenerateForLevel() error = %v", err)
+	}
+	if ok, _ := VerifyPassphrase(passphrase, 8, LanguageEnglish); !ok {
+		t.Errorf("GenerateForLevel(SecurityHigh) = %q, want 8 English words", passphrase)
+	}
+}
+
+func TestParseSecurityLevel(t *testing.T) {
+	for _, level := range []SecurityLevel{SecurityLow, SecurityMedium, SecurityHigh, SecurityParanoid} {
+		got, err := ParseSecurityLevel(level.String())
+		if err != nil || got != level {
+			t.Errorf("ParseSecurityLevel(%q) = %v, %v, want %v", level.String(), got, err, level)
+		}
+	}
+	if got, err := ParseSecurityLevel("HIGH"); err != nil || got != SecurityHigh {
+		t.Errorf("ParseSecurityLevel(%q) = %v, %v, want SecurityHigh", "HIGH", got, err)
+	}
+	if _, err := ParseSecurityLevel("extreme"); err == nil {
+		t.Error("ParseSecurityLevel(\"extreme\") should return an error")
+	}
+	if got := SecurityLevel(99).String(); got != "SecurityLevel(99)" {
+		t.Errorf("String() = %q, want %q", got, "SecurityLevel(99)")
+	}
+}
@@ -0,0 +1,103 @@
+package diceware
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// nistMinLength is the minimum length NIST SP 800-63B (section 5.1.1.1)
+// sets for memorized secrets chosen by the subscriber. Secrets the service
+// generates randomly may be as short as 6 characters; the stricter bound
+// is used since MeetsNIST can't tell how a passphrase was chosen.
+const nistMinLength = 8
+
+// commonPasswords are among the most frequent passwords in public breach
+// corpora. SP 800-63B requires verifiers to reject secrets found in such
+// lists; this short one catches the usual suspects without shipping a
+// breach database.
+var commonPasswords = map[string]bool{
+	"123456": true, "123456789": true, "12345678": true, "password": true,
+	"qwerty": true, "qwerty123": true, "1q2w3e4r": true, "12345": true,
+	"1234567890": true, "1234567": true, "111111": true, "123123": true,
+	"abc123": true, "password1": true, "iloveyou": true, "000000": true,
+	"qwertyuiop": true, "123321": true, "dragon": true, "sunshine": true,
+	"princess": true, "letmein": true, "monkey": true, "football": true,
+	"baseball": true, "welcome": true, "admin": true, "login": true,
+	"master": true, "trustno1": true, "passw0rd": true, "starwars": true,
+	"superman": true, "shadow": true, "michael": true, "whatever": true,
+	"zaq12wsx": true, "1qaz2wsx": true, "asdfghjkl": true, "p@ssw0rd": true,
+	"correcthorsebatterystaple": true,
+}
+
+// MeetsNIST checks passphrase against the NIST SP 800-63B recommendations
+// for memorized secrets that can be checked from the secret alone:
+//
+//   - it is at least 8 characters long;
+//   - it isn't a commonly used or breached password (a short built-in list
+//     of the most frequent ones, ignoring case and separators);
+//   - it isn't a single dictionary word from one of the built-in wordlists;
+//   - it isn't made of repetitive or sequential characters, like
+//     "aaaaaaaa" or "1234abcd".
+//
+// It reports whether every check passes, along with a description of each
+// failed one. Context-specific words (the service or user name) and full
+// breach corpora are beyond what a library can check; a passphrase of
+// several generated words passes everything else.
+func MeetsNIST(passphrase string) (bool, []string) {
+	var failed []string
+
+	if n := utf8.RuneCountInString(passphrase); n < nistMinLength {
+		failed = append(failed, fmt.Sprintf("is %d characters long, shorter than the minimum of %d", n, nistMinLength))
+	}
+
+	folded := strings.ToLower(strings.Map(func(r rune) rune {
+		if strings.ContainsRune(" -_.", r) {
+			return -1
+		}
+		return r
+	}, passphrase))
+	if commonPasswords[folded] {
+		failed = append(failed, "is a commonly used password")
+	}
+	if isDictionaryWord(passphrase) {
+		failed = append(failed, "is a single dictionary word")
+	}
+	if passphrase != "" && isSequential(folded) {
+		failed = append(failed, "consists of repetitive or sequential characters")
+	}
+
+	return len(failed) == 0, failed
+}
+
+// isDictionaryWord reports whether word, ignoring case, is a usable word of
+// one of the built-in wordlists.
+func isDictionaryWord(word string) bool {
+	for lang := LanguageEnglish; lang < numBuiltinLanguages; lang++ {
+		if lang == LanguageMixed {
+			continue
+		}
+		if wl, ok := lookupWordlist(lang); ok {
+			if _, ok := wl.lookupWord(word); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isSequential reports whether s is at most two runs of characters that
+// repeat or step by one, like "aaaaaa", "87654321" or "1234abcd".
+func isSequential(s string) bool {
+	runs := 1
+	prev := rune(-1)
+	for i, r := range s {
+		if i > 0 {
+			if d := r - prev; d < -1 || d > 1 {
+				runs++
+			}
+		}
+		prev = r
+	}
+	return runs <= 2
+}
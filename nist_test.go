@@ -0,0 +1,49 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMeetsNIST(t *testing.T) {
+	tests := []struct {
+		passphrase string
+		failures   []string // substrings of the expected failures, in order
+	}{
+		{"Oppressor-Reroute-Moonstone-Disown", nil},
+		{"CorrectHorse", nil},
+		{"Colt", []string{"shorter than the minimum of 8", "dictionary word"}},
+		{"Password", []string{"commonly used", "dictionary word"}},
+		{"Qwerty123", []string{"commonly used"}},
+		{"correct horse battery staple", []string{"commonly used"}},
+		{"Moonstone", []string{"dictionary word"}},
+		{"aaaaaaaa", []string{"repetitive or sequential"}},
+		{"1234abcd", []string{"repetitive or sequential"}},
+		{"87654321", []string{"repetitive or sequential"}},
+		{"", []string{"is 0 characters long"}},
+	}
+
+	for _, tt := range tests {
+		ok, failed := MeetsNIST(tt.passphrase)
+		if ok != (len(tt.failures) == 0) || len(failed) != len(tt.failures) {
+			t.Errorf("MeetsNIST(%q) = %v, %q, want failures matching %q", tt.passphrase, ok, failed, tt.failures)
+			continue
+		}
+		for i, want := range tt.failures {
+			if !strings.Contains(failed[i], want) {
+				t.Errorf("MeetsNIST(%q) failure %d = %q, want it to mention %q", tt.passphrase, i, failed[i], want)
+			}
+		}
+	}
+
+	// Generated passphrases of the recommended size always pass
+	for i := 0; i < 20; i++ {
+		passphrase, err := Generate(4)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok, failed := MeetsNIST(passphrase); !ok {
+			t.Errorf("MeetsNIST(%q) failed: %q", passphrase, failed)
+		}
+	}
+}
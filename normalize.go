@@ -0,0 +1,59 @@
+package diceware
+
+// nfcCompositions maps a base letter followed by a combining diacritic to
+// the precomposed letter Unicode Normalization Form C uses for the pair. It
+// covers the letters of asciiFolds, Romanian among them, which is what
+// Latin-script wordlists contain.
+var nfcCompositions = map[[2]rune]rune{
+	// Grave, U+0300
+	{'a', '\u0300'}: 'à', {'A', '\u0300'}: 'À', {'e', '\u0300'}: 'è', {'E', '\u0300'}: 'È',
+	{'i', '\u0300'}: 'ì', {'I', '\u0300'}: 'Ì', {'o', '\u0300'}: 'ò', {'O', '\u0300'}: 'Ò',
+	{'u', '\u0300'}: 'ù', {'U', '\u0300'}: 'Ù',
+	// Acute, U+0301
+	{'a', '\u0301'}: 'á', {'A', '\u0301'}: 'Á', {'e', '\u0301'}: 'é', {'E', '\u0301'}: 'É',
+	{'i', '\u0301'}: 'í', {'I', '\u0301'}: 'Í', {'o', '\u0301'}: 'ó', {'O', '\u0301'}: 'Ó',
+	{'u', '\u0301'}: 'ú', {'U', '\u0301'}: 'Ú', {'y', '\u0301'}: 'ý', {'Y', '\u0301'}: 'Ý',
+	// Circumflex, U+0302
+	{'a', '\u0302'}: 'â', {'A', '\u0302'}: 'Â', {'i', '\u0302'}: 'î', {'I', '\u0302'}: 'Î',
+	{'e', '\u0302'}: 'ê', {'E', '\u0302'}: 'Ê', {'o', '\u0302'}: 'ô', {'O', '\u0302'}: 'Ô',
+	{'u', '\u0302'}: 'û', {'U', '\u0302'}: 'Û',
+	// Tilde, U+0303
+	{'a', '\u0303'}: 'ã', {'A', '\u0303'}: 'Ã', {'n', '\u0303'}: 'ñ', {'N', '\u0303'}: 'Ñ',
+	{'o', '\u0303'}: 'õ', {'O', '\u0303'}: 'Õ',
+	// Breve, U+0306
+	{'a', '\u0306'}: 'ă', {'A', '\u0306'}: 'Ă',
+	// Diaeresis, U+0308
+	{'a', '\u0308'}: 'ä', {'A', '\u0308'}: 'Ä', {'e', '\u0308'}: 'ë', {'E', '\u0308'}: 'Ë',
+	{'i', '\u0308'}: 'ï', {'I', '\u0308'}: 'Ï', {'o', '\u0308'}: 'ö', {'O', '\u0308'}: 'Ö',
+	{'u', '\u0308'}: 'ü', {'U', '\u0308'}: 'Ü', {'y', '\u0308'}: 'ÿ',
+	// Ring above, U+030A
+	{'a', '\u030a'}: 'å', {'A', '\u030a'}: 'Å',
+	// Comma below, U+0326
+	{'s', '\u0326'}: 'ș', {'S', '\u0326'}: 'Ș', {'t', '\u0326'}: 'ț', {'T', '\u0326'}: 'Ț',
+	// Cedilla, U+0327
+	{'s', '\u0327'}: 'ş', {'S', '\u0327'}: 'Ş', {'t', '\u0327'}: 'ţ', {'T', '\u0327'}: 'Ţ',
+	{'c', '\u0327'}: 'ç', {'C', '\u0327'}: 'Ç',
+}
+
+// toNFC returns word in Normalization Form C as far as nfcCompositions
+// goes, so that a word typed or saved with decomposed diacritics ("s" +
+// U+0326) has the same bytes as the precomposed one ("ș"). Combining marks
+// without an entry are kept as they are.
+func toNFC(word string) string {
+	if isASCII(word) {
+		return word
+	}
+
+	runes := []rune(word)
+	out := runes[:0]
+	for _, r := range runes {
+		if n := len(out); n > 0 {
+			if c, ok := nfcCompositions[[2]rune{out[n-1], r}]; ok {
+				out[n-1] = c
+				continue
+			}
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
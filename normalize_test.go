@@ -0,0 +1,53 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToNFC(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"colt", "colt"},
+		{"șarpe", "șarpe"},         // ș, comma below
+		{"şi", "şi"},               // ş, cedilla stays cedilla
+		{"Țara", "Țara"},           // Ț
+		{"mămăligă", "mămăligă"}, // ă
+		{"încet", "încet"},         // î
+		{"încet", "încet"},          // already NFC
+		{"́acute", "́acute"},        // nothing to compose with
+		{"x́", "x́"},                // no precomposed form
+	}
+	for _, tt := range tests {
+		if got := toNFC(tt.in); got != tt.want {
+			t.Errorf("toNFC(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestWordlistNFC(t *testing.T) {
+	// The same words, decomposed in the file and precomposed in the map
+	parsed, err := LoadWordlist("nfd", strings.NewReader("1111 șarpe\n1112 pâine\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	built, err := NewWordlist("nfc", map[string]string{"1111": "șarpe", "1112": "pâine"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wl := range []*Wordlist{parsed, built} {
+		if got, want := strings.Join(wl.Words(), " "), "pâine șarpe"; got != want {
+			t.Errorf("%s words = %q, want precomposed %q", wl.Name(), got, want)
+		}
+		for _, word := range []string{"șarpe", "Șarpe"} {
+			if _, ok := wl.lookupWord(word); !ok {
+				t.Errorf("%s: %q not found", wl.Name(), word)
+			}
+		}
+	}
+
+	// Blocklisted words match whatever their form
+	got, err := GenerateWithOptions(1, WithWordlists(parsed), WithBlocklist([]string{"șarpe"}))
+	if err != nil || got != "Pâine" {
+		t.Errorf("GenerateWithOptions() = %q, %v, want %q", got, err, "Pâine")
+	}
+}
@@ -0,0 +1,1319 @@
+package diceware
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxLengthAttempts bounds how many whole passphrases generate draws while
+// looking for one that fits a WithMinLength/WithMaxLength window.
+const maxLengthAttempts = 1000
+
+// defaultMixedRatio is the probability that LanguageMixed draws a word from
+// the English wordlist rather than the Romanian one: a fair coin flip.
+const defaultMixedRatio = 0.5
+
+// Option configures passphrase generation. Options are passed to
+// GenerateWithOptions and EntropyWithOptions; later options override
+// earlier ones.
+//
+// Example:
+//
+//	passphrase, err := diceware.GenerateWithOptions(6,
+//	    diceware.WithLanguage(diceware.LanguageMixed),
+//	    diceware.WithMixedRatio(0.75),
+//	    diceware.WithSeparator("-"),
+//	)
+type Option func(*options)
+
+// options holds the settings Option functions modify. The zero value is not
+// meaningful; use newOptions.
+type options struct {
+	lang         Language
+	alternate    []Language // WithAlternatingLanguages, overrides lang
+	separators   []string
+	randomSeps   []string // WithSeparatorRandom, overrides separators
+	leadingSep   bool
+	trailingSep  bool
+	connectors   bool
+	prefix       string
+	suffix       string
+	mixedRatio   float64
+	wordlists    []*Wordlist
+	sourceErr    error // a WithWordlistSources source that failed to load
+	minLength    int
+	maxLength    int
+	numDigits    int
+	asciiFold    bool
+	unique       bool
+	groupSize    int
+	groupSep     string
+	blocklist    map[string]bool
+	minWordLen   int
+	wordFilter   func(word string) bool
+	startWith    map[rune]bool // WithStartLetters, lowercased
+	capMode      CapitalizationMode
+	casePattern  []CapitalizationMode // WithCasePattern, overrides capMode
+	capitalize   func(string) string  // WithCapitalizer, overrides both
+	transform    func(word string, index int) string
+	minEntropy   float64
+	maxAttempts  int
+	randAttempts int
+	observer     func(wordIndex int, roll, word string)
+
+	// srcLists and srcWeights cache sources(), which applies the blocklist
+	// by deriving filtered wordlists.
+	srcLists   []*Wordlist
+	srcWeights []float64
+	// slotWeights holds, for each WithAlternatingLanguages language in
+	// turn, the weights of the sources that draw only from its lists.
+	slotWeights [][]float64
+	// firstLists caches firstSources, the sources further filtered by
+	// WithStartLetters.
+	firstLists []*Wordlist
+
+	// rand is the source of all randomness: crypto/rand.Reader unless
+	// WithRandReader or a seeded Generator swaps it out.
+	rand io.Reader
+}
+
+// newOptions returns the default settings (English, no separator, fair
+// mixed-mode coin flip) with opts applied on top.
+func newOptions(opts ...Option) *options {
+	o := &options{
+		lang:       LanguageEnglish,
+		mixedRatio: defaultMixedRatio,
+		rand:       rand.Reader,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// validate reports settings that can't be used for generation.
+func (o *options) validate() error {
+	if o.sourceErr != nil {
+		return o.sourceErr
+	}
+	if o.wordlists != nil {
+		if len(o.wordlists) == 0 {
+			return errors.New("at least one wordlist is required")
+		}
+		for i, wl := range o.wordlists {
+			if wl == nil {
+				return fmt.Errorf("wordlist %d is nil", i+1)
+			}
+		}
+	} else if o.alternate != nil {
+		if len(o.alternate) == 0 {
+			return errors.New("at least one language to alternate is required")
+		}
+		for _, lang := range o.alternate {
+			if WordlistSizeByLanguage(lang) == 0 {
+				return unsupportedLanguage(lang)
+			}
+		}
+	} else if WordlistSizeByLanguage(o.lang) == 0 {
+		return unsupportedLanguage(o.lang)
+	}
+	if o.minLength < 0 || o.maxLength < 0 {
+		return fmt.Errorf("length limits must not be negative, got min %d, max %d", o.minLength, o.maxLength)
+	}
+	if o.maxLength > 0 && o.minLength > o.maxLength {
+		return fmt.Errorf("minimum length %d exceeds maximum length %d", o.minLength, o.maxLength)
+	}
+	if !o.capMode.valid() {
+		return fmt.Errorf("unknown capitalization mode: %v", o.capMode)
+	}
+	for _, mode := range o.casePattern {
+		if !mode.valid() {
+			return fmt.Errorf("unknown capitalization mode in case pattern: %v", mode)
+		}
+	}
+	if o.groupSize < 0 {
+		return fmt.Errorf("group size must not be negative, got %d", o.groupSize)
+	}
+	if o.numDigits < 0 || o.numDigits > maxNumberDigits {
+		return fmt.Errorf("number word must have 1 to %d digits, got %d", maxNumberDigits, o.numDigits)
+	}
+	if math.IsNaN(o.mixedRatio) || o.mixedRatio < 0 || o.mixedRatio > 1 {
+		return fmt.Errorf("mixed ratio must be between 0 and 1, got %v", o.mixedRatio)
+	}
+	if o.randomSeps != nil {
+		if err := checkSeparatorSet(o.randomSeps); err != nil {
+			return err
+		}
+	}
+	if math.IsNaN(o.minEntropy) || o.minEntropy < 0 {
+		return fmt.Errorf("minimum entropy must not be negative, got %v", o.minEntropy)
+	}
+	if o.maxAttempts < 0 {
+		return fmt.Errorf("maximum attempts must not be negative, got %d", o.maxAttempts)
+	}
+	if o.randAttempts < 0 {
+		return fmt.Errorf("random source attempts must not be negative, got %d", o.randAttempts)
+	}
+	if o.minWordLen < 0 {
+		return fmt.Errorf("minimum word length must not be negative, got %d", o.minWordLen)
+	}
+	if o.filtered() && o.poolSize() == 0 {
+		if o.wordFilter != nil {
+			return errors.New("the word filter rejects every word")
+		}
+		if o.minWordLen > 0 {
+			return fmt.Errorf("no usable words are at least %d characters long", o.minWordLen)
+		}
+		return errors.New("the blocklist excludes every word")
+	}
+	if o.alternate != nil && o.filtered() {
+		lists, _ := o.sources()
+		for i, weights := range o.slotWeights {
+			if pickableSize(lists, weights) == 0 {
+				return fmt.Errorf("no usable words are left in alternating language %v", o.alternate[i])
+			}
+		}
+	}
+	if o.startWith != nil && o.firstPoolSize() == 0 {
+		letters := make([]rune, 0, len(o.startWith))
+		for r := range o.startWith {
+			letters = append(letters, r)
+		}
+		slices.Sort(letters)
+		return fmt.Errorf("no usable word starts with any of %q", string(letters))
+	}
+	return nil
+}
+
+// WithLanguage selects the wordlist(s) to draw words from. The default is
+// LanguageEnglish.
+func WithLanguage(lang Language) Option {
+	return func(o *options) {
+		o.lang = lang
+		o.alternate = nil
+	}
+}
+
+// WithAlternatingLanguages draws each word from the languages in turn
+// instead of a single one, e.g. WithAlternatingLanguages([]Language{
+// LanguageEnglish, LanguageRomanian}) yields an English word, a Romanian
+// one, an English one and so on, for predictable bilingual passphrases
+// rather than LanguageMixed's coin flip per word. The words themselves are
+// still drawn at random from their language's wordlist.
+//
+// The language sequence is fixed, so it adds no entropy: each word counts
+// the bits of its own language, which for an English/Romanian alternation
+// is about 1 bit per word less than LanguageMixed. It
+// overrides WithLanguage and WithWordlists; a LanguageMixed entry flips
+// its coin (see WithMixedRatio) as usual.
+func WithAlternatingLanguages(langs []Language) Option {
+	return func(o *options) {
+		o.alternate = append([]Language{}, langs...)
+		o.wordlists = nil
+		o.sourceErr = nil
+	}
+}
+
+// WithWordlists draws words from the given wordlists instead of a built-in
+// language, picking one of the lists uniformly at random for each word. It
+// overrides WithLanguage and WithAlternatingLanguages. See
+// GenerateFromWordlists.
+func WithWordlists(lists ...*Wordlist) Option {
+	return func(o *options) {
+		o.wordlists = append([]*Wordlist{}, lists...)
+		o.alternate = nil
+		o.sourceErr = nil
+	}
+}
+
+// WithSeparator sets the string placed between words. The default is no
+// separator (CamelCase).
+func WithSeparator(separator string) Option {
+	return func(o *options) {
+		o.separators = []string{separator}
+		o.randomSeps = nil
+	}
+}
+
+// WithSeparators cycles through the given separators between words instead
+// of using a single fixed one, e.g. WithSeparators([]string{"-", "_"})
+// produces "Colt-Default_Arousal-Thimble". A single-element slice behaves
+// like WithSeparator, and an empty one means no separator. It overrides
+// WithSeparator.
+func WithSeparators(separators []string) Option {
+	return func(o *options) {
+		o.separators = append([]string{}, separators...)
+		o.randomSeps = nil
+	}
+}
+
+// WithSeparatorRandom picks the separator for each gap between words
+// uniformly at random from set, e.g. WithSeparatorRandom([]string{"-", "_",
+// ".", "+"}) yields passphrases like "Colt.Default-Arousal+Thimble", to
+// satisfy "must contain a symbol" rules without always using the same
+// symbol. Separators go between words, and before the first or after the
+// last only with WithLeadingSeparator or WithTrailingSeparator.
+//
+// Each gap adds log2(len(set)) bits of entropy, which EntropyWithOptions
+// includes. The set must be non-empty and its separators non-empty and
+// distinct. It overrides WithSeparator and WithSeparators.
+func WithSeparatorRandom(set []string) Option {
+	return func(o *options) {
+		o.randomSeps = append([]string{}, set...)
+		o.separators = nil
+	}
+}
+
+// WithLeadingSeparator places a separator before the first word as well,
+// e.g. "-Colt-Default-Arousal", for fields with a fixed format. With
+// WithSeparators the leading separator takes the first turn in the cycle,
+// and with WithSeparatorRandom it is drawn like the others, adding its
+// bits to EntropyWithOptions. It has no effect without a separator.
+func WithLeadingSeparator(leading bool) Option {
+	return func(o *options) {
+		o.leadingSep = leading
+	}
+}
+
+// WithTrailingSeparator places a separator after the last word as well,
+// e.g. "Colt-Default-Arousal-". It works like WithLeadingSeparator, the
+// trailing separator taking the last turn.
+func WithTrailingSeparator(trailing bool) Option {
+	return func(o *options) {
+		o.trailingSep = trailing
+	}
+}
+
+// connectorWords are the short words WithConnectorWords puts between the
+// words of a passphrase. There are 16, so each adds 4 bits.
+var connectorWords = []string{
+	"a", "an", "and", "as", "at", "but", "by", "for",
+	"from", "in", "of", "on", "or", "the", "to", "with",
+}
+
+// WithConnectorWords puts a short connector word, picked at random from a
+// small built-in list ("and", "the", "of", ...), between each pair of
+// words so the passphrase reads more like a sentence: with WithSeparator("
+// ") and CapAdaptive, e.g. "Colt of default the arousal". The connectors
+// sit between two copies of the gap's separator and stay lowercase; use a
+// separator, as without one they run into the words.
+//
+// Each connector adds 4 bits of entropy, which EntropyWithOptions counts
+// under EntropyBreakdown.Decorations, and their letters count towards
+// WithMinLength and WithMaxLength. A fixed connector adds nothing: for
+// that, use WithSeparator(" and ") instead. Passphrase.Words and the
+// other word slices don't include the connectors.
+func WithConnectorWords(enabled bool) Option {
+	return func(o *options) {
+		o.connectors = enabled
+	}
+}
+
+// WithPrefix prepends prefix verbatim to every passphrase, e.g. "ACME-"
+// for "ACME-ColtDefaultArousal", for systems that require passwords to
+// start with a fixed tag. It is added last, after WithGrouping, and is
+// counted by WithMinLength and WithMaxLength. A fixed prefix adds no
+// entropy, and EntropyWithOptions counts none.
+func WithPrefix(prefix string) Option {
+	return func(o *options) {
+		o.prefix = prefix
+	}
+}
+
+// WithSuffix appends suffix verbatim to every passphrase, like WithPrefix,
+// e.g. "-2024" for "ColtDefaultArousal-2024". It adds no entropy either.
+func WithSuffix(suffix string) Option {
+	return func(o *options) {
+		o.suffix = suffix
+	}
+}
+
+// WithMixedRatio sets the probability (0 to 1) that LanguageMixed draws each
+// word from the English wordlist rather than the Romanian one. The default
+// is 0.5, a fair coin flip; WithMixedRatio(0.75) yields roughly 75% English
+// words. It has no effect for other languages.
+//
+// A biased ratio makes words from the less likely wordlist rarer, so the
+// entropy per word drops below the fair-coin figure. EntropyWithOptions
+// accounts for this.
+func WithMixedRatio(english float64) Option {
+	return func(o *options) {
+		o.mixedRatio = english
+	}
+}
+
+// WithMinLength requires the joined passphrase, separators included, to be at
+// least n characters long. Passphrases that come out shorter are discarded
+// and generated again from scratch. 0 (the default) means no minimum.
+//
+// Discarding passphrases removes them from the pool of possible results, so
+// the entropy is somewhat lower than EntropyWithOptions reports; keep the
+// window loose relative to the typical length (about 7 characters per
+// English word) so that the loss stays negligible.
+func WithMinLength(n int) Option {
+	return func(o *options) {
+		o.minLength = n
+	}
+}
+
+// WithMaxLength requires the joined passphrase, separators included, to be
+// at most n characters long, e.g. to fit a form's maxlength. It works like
+// WithMinLength. 0 (the default) means no maximum.
+func WithMaxLength(n int) Option {
+	return func(o *options) {
+		o.maxLength = n
+	}
+}
+
+// WithASCIIFold transliterates letters with diacritics to their base ASCII
+// letters (ș→s, ț→t, ă→a, ...) after each word is drawn, for systems that
+// can't store non-ASCII passwords. ASCII words, including every English
+// word, are left untouched. Generation returns an error for a word with a
+// non-ASCII character the mapping table doesn't cover.
+//
+// Folding happens after selection, so it doesn't change which words can be
+// drawn or how likely they are. It can only lose entropy if a wordlist has
+// two words that differ in diacritics alone (e.g. "fata" and "fată"); the
+// built-in lists have none.
+func WithASCIIFold(fold bool) Option {
+	return func(o *options) {
+		o.asciiFold = fold
+	}
+}
+
+// WithCapitalizer replaces the default casing of each drawn word (first
+// letter in title case) with fn, e.g. to apply locale-specific rules like
+// Turkish dotted/dotless i without this package depending on a text
+// library:
+//
+//	diceware.WithCapitalizer(func(word string) string {
+//	    return strings.ToUpperSpecial(unicode.TurkishCase, word[:1]) + word[1:]
+//	})
+//
+// fn must be deterministic; casing is applied after selection and is not
+// counted as entropy. It overrides WithCapitalization and WithCasePattern;
+// passing nil restores them.
+func WithCapitalizer(fn func(word string) string) Option {
+	return func(o *options) {
+		o.capitalize = fn
+	}
+}
+
+// WithWordTransform applies fn to each word after it is drawn and cased,
+// before the words are joined, for styling the package has no option for,
+// e.g. leetspeak or truncation:
+//
+//	diceware.WithWordTransform(func(word string, index int) string {
+//	    return strings.NewReplacer("e", "3", "o", "0").Replace(word)
+//	})
+//
+// index is the word's 0-based position in the passphrase. The
+// WithNumberWord number isn't passed to fn.
+//
+// Transforms are not counted as entropy: a deterministic one adds none,
+// since an attacker can apply it too, and one that maps different words to
+// the same result (like truncation) leaves less entropy than
+// EntropyWithOptions reports. If fn makes random choices, estimate the
+// bits they add yourself and report them separately. WithUniqueWords and
+// WithBlocklist look at the words before fn is applied.
+func WithWordTransform(fn func(word string, index int) string) Option {
+	return func(o *options) {
+		o.transform = fn
+	}
+}
+
+// WithBlocklist excludes words from passphrases, e.g. offensive or reserved
+// words: rolls landing on a listed word are rerolled, matching words
+// case-insensitively. The excluded words no longer count towards the pool
+// size, so EntropyWithOptions reports correspondingly less entropy.
+//
+// Generation returns an error if the blocklist leaves no usable words, or
+// too few for WithUniqueWords. Later calls replace earlier ones.
+func WithBlocklist(words []string) Option {
+	return func(o *options) {
+		o.blocklist = make(map[string]bool, len(words))
+		for _, word := range words {
+			o.blocklist[strings.ToLower(toNFC(strings.TrimSpace(word)))] = true
+		}
+	}
+}
+
+// WithMinWordLength rerolls words shorter than n characters, e.g. to avoid
+// short words like "ad" or "an", which are hard to pick out of a
+// concatenated passphrase. Like WithBlocklist, the excluded words
+// no longer count towards the pool size, so EntropyWithOptions reports
+// correspondingly less entropy. 0 (the default) allows every word.
+//
+// Generation returns an error if no usable word is long enough.
+func WithMinWordLength(n int) Option {
+	return func(o *options) {
+		o.minWordLen = n
+	}
+}
+
+// WithWordFilter draws words only from the subset of the wordlists keep
+// accepts, for themed passphrases such as "friendly room names" from a
+// list of animals, or only words of 4 to 6 letters. keep is called once
+// per word of the lists, as it appears in the list (before
+// capitalization), each time options are applied. Rolls landing on a
+// rejected word are rerolled, so the draw stays uniform over the subset,
+// and EntropyWithOptions counts only the words keep accepts: a subset of
+// 500 English words gives about 9 bits per word instead of 12.9, so plan
+// the word count accordingly. nil (the default) keeps every word.
+//
+// Generation returns an error if keep rejects every usable word, or leaves
+// too few for WithUniqueWords.
+func WithWordFilter(keep func(word string) bool) Option {
+	return func(o *options) {
+		o.wordFilter = keep
+	}
+}
+
+// WithStartLetters rerolls the first word of the passphrase until it
+// begins with one of letters, ignoring case, e.g. for acrostic-style memory
+// aids. The other words are unconstrained. Only the matching words count
+// towards the first word's pool, so EntropyWithOptions reports
+// correspondingly less entropy: for a single letter in English, from about
+// 2.8 bits less for "s" to 11.9 for "x". An empty letters removes the
+// constraint.
+//
+// Generation returns an error if no usable word starts with any of the
+// letters.
+func WithStartLetters(letters []rune) Option {
+	return func(o *options) {
+		if len(letters) == 0 {
+			o.startWith = nil
+			return
+		}
+		o.startWith = make(map[rune]bool, len(letters))
+		for _, r := range letters {
+			o.startWith[unicode.ToLower(r)] = true
+		}
+	}
+}
+
+// startsRight reports whether word begins with one of the WithStartLetters
+// letters.
+func (o *options) startsRight(word string) bool {
+	r, _ := utf8.DecodeRuneInString(word)
+	return o.startWith[unicode.ToLower(r)]
+}
+
+// WithRollObserver calls fn with each word as it is drawn: its position in
+// the passphrase, the dice roll that selected it and the word as it will
+// appear, e.g. to animate the dice in a TUI or an educational
+// visualization. Rolls landing on unusable or (with WithUniqueWords)
+// repeated words are redrawn before fn sees them, but when WithMinLength or
+// WithMaxLength discard a whole passphrase fn has already seen its words,
+// with wordIndex starting over at 0. A WithNumberWord number isn't rolled
+// and isn't reported.
+//
+// fn only receives copies of the results; it can't influence the
+// randomness. It is meant for debugging and demos: never log the rolls or
+// words of real passphrases, as they are the secret.
+func WithRollObserver(fn func(wordIndex int, roll, word string)) Option {
+	return func(o *options) {
+		o.observer = fn
+	}
+}
+
+// WithMaxAttempts sets how many times each rerolling step of generation
+// tries before giving up with an error wrapping ErrMaxAttempts that says
+// how many attempts it made: rerolling a word that is unusable, blocklisted
+// or too short, redrawing a WithUniqueWords duplicate, and regenerating a
+// passphrase outside the WithMinLength/WithMaxLength window. It bounds the
+// worst case of a request path, e.g. WithMaxAttempts(10) for at most 10
+// rerolls per word and 10 passphrases per call.
+//
+// The defaults scale with how much a step rejects and make giving up
+// practically impossible for satisfiable settings; a lower limit trades
+// that for predictability, and fails more often the tighter the
+// constraints. 0 restores the defaults.
+func WithMaxAttempts(n int) Option {
+	return func(o *options) {
+		o.maxAttempts = n
+	}
+}
+
+// RequireMinEntropy makes generation fail fast with ErrInsufficientEntropy
+// if the word count and the other options (see EntropyWithOptions) give
+// less than bits of entropy, instead of silently producing a weak
+// passphrase. For example, 3 English words (~38.8 bits) with
+// RequireMinEntropy(78) fail, while 7 words pass. It lets code embedding
+// this package enforce a policy at the call site. 0 (the default) accepts
+// any entropy.
+func RequireMinEntropy(bits float64) Option {
+	return func(o *options) {
+		o.minEntropy = bits
+	}
+}
+
+// WithUniqueWords makes every word of a passphrase distinct, redrawing words
+// that already came up. Words are compared as shown, ignoring case, rather
+// than by roll, so a word both lists of LanguageMixed (or WithWordlists)
+// share, like "abator", can't appear twice from different lists either.
+//
+// Ruling out repeats slightly lowers the entropy, which EntropyWithOptions
+// accounts for; with 7,776-word lists the difference is a fraction of a
+// bit. Generation returns an error if wordCount exceeds the number of
+// usable words.
+func WithUniqueWords(unique bool) Option {
+	return func(o *options) {
+		o.unique = unique
+	}
+}
+
+// WithGrouping regroups the final passphrase into chunks of size characters
+// joined by separator, e.g. WithGrouping(4, "-") turns "ColtDefaultArousal"
+// into "Colt-Defa-ultA-rous-al", which some people find easier to read out
+// over the phone. It is purely cosmetic and applied after the words are
+// joined; the entropy is unchanged. WithMinLength and WithMaxLength count
+// the grouped result. 0 (the default) disables grouping.
+func WithGrouping(size int, separator string) Option {
+	return func(o *options) {
+		o.groupSize = size
+		o.groupSep = separator
+	}
+}
+
+// maxNumberDigits is the longest number WithNumberWord can add.
+const maxNumberDigits = 4
+
+// WithNumberWord inserts a random number of exactly digits digits (1-4,
+// zero-padded, e.g. "0427") at a random word boundary, to satisfy "must
+// contain a number" password rules. It adds digits*log2(10) bits of
+// entropy, which EntropyWithOptions includes; the random position adds a
+// little more that isn't counted.
+//
+// When no separator is used, the number is never placed next to a word
+// that starts or ends with a digit (only possible with custom wordlists),
+// so it can't run into other digits. 0 (the default) adds no number.
+func WithNumberWord(digits int) Option {
+	return func(o *options) {
+		o.numDigits = digits
+	}
+}
+
+// GenerateWithOptions creates a passphrase with the specified number of words
+// configured by opts. With no options it behaves like Generate.
+//
+// Returns an error if wordCount is less than 1, if the options are invalid,
+// or if random number generation fails.
+func GenerateWithOptions(wordCount int, opts ...Option) (string, error) {
+	o := newOptions(opts...)
+	passphrase, _, _, err := generate(wordCount, o)
+	return passphrase, err
+}
+
+// checkSeparatorSet validates the set passed to WithSeparatorRandom.
+func checkSeparatorSet(set []string) error {
+	if len(set) == 0 {
+		return errors.New("random separator set must not be empty")
+	}
+	seen := make(map[string]bool, len(set))
+	for _, sep := range set {
+		if sep == "" {
+			return errors.New("random separators must not be empty")
+		}
+		if seen[sep] {
+			return fmt.Errorf("duplicate random separator %q", sep)
+		}
+		seen[sep] = true
+	}
+	return nil
+}
+
+// gapCount returns how many separators a passphrase of n tokens has: one
+// between each pair, plus the leading and trailing ones if configured.
+func (o *options) gapCount(n int) int {
+	gaps := n - 1
+	if o.leadingSep {
+		gaps++
+	}
+	if o.trailingSep {
+		gaps++
+	}
+	return gaps
+}
+
+// separated reports whether the words are separated by anything, i.e.
+// whether any configured separator is non-empty. Random separators never
+// are empty.
+func (o *options) separated() bool {
+	if o.randomSeps != nil {
+		return true
+	}
+	for _, sep := range o.separators {
+		if sep != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// drawGaps picks the WithSeparatorRandom separators for n gaps (see
+// gapCount), or returns nil if the separators are fixed.
+func (o *options) drawGaps(n int) ([]string, error) {
+	if o.randomSeps == nil || n < 1 {
+		return nil, nil
+	}
+	gaps := make([]string, n)
+	for i := range gaps {
+		k, err := rand.Int(o.rand, big.NewInt(int64(len(o.randomSeps))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to pick separator: %w", randomSourceError(err))
+		}
+		gaps[i] = o.randomSeps[k.Int64()]
+	}
+	return gaps, nil
+}
+
+// drawConnectors picks the WithConnectorWords connectors for n tokens, one
+// between each pair, or returns nil if they're off.
+func (o *options) drawConnectors(n int) ([]string, error) {
+	if !o.connectors || n < 2 {
+		return nil, nil
+	}
+	conns := make([]string, n-1)
+	for i := range conns {
+		k, err := rand.Int(o.rand, big.NewInt(int64(len(connectorWords))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to pick connector word: %w", randomSourceError(err))
+		}
+		conns[i] = connectorWords[k.Int64()]
+	}
+	return conns, nil
+}
+
+// join concatenates words, filling the gaps (see gapCount) in order with
+// gaps if it is non-nil (see drawGaps) and the configured separators in
+// turn otherwise, with conns (see drawConnectors) between two copies of
+// the separator of each gap between words if non-nil, then applies
+// WithGrouping and adds the WithPrefix and WithSuffix strings.
+func (o *options) join(words, gaps, conns []string) string {
+	gap := 0
+	next := func() string {
+		gap++
+		switch {
+		case gaps != nil:
+			return gaps[gap-1]
+		case len(o.separators) == 0:
+			return ""
+		default:
+			return o.separators[(gap-1)%len(o.separators)]
+		}
+	}
+
+	var b strings.Builder
+	if o.leadingSep {
+		b.WriteString(next())
+	}
+	for i, word := range words {
+		if i > 0 {
+			sep := next()
+			b.WriteString(sep)
+			if conns != nil {
+				b.WriteString(conns[i-1])
+				b.WriteString(sep)
+			}
+		}
+		b.WriteString(word)
+	}
+	if o.trailingSep {
+		b.WriteString(next())
+	}
+	joined := b.String()
+
+	if o.groupSize > 0 {
+		joined = group(joined, o.groupSize, o.groupSep)
+	}
+	return o.prefix + joined + o.suffix
+}
+
+// groupedLength returns the length of an n-character joined passphrase
+// after WithGrouping.
+func (o *options) groupedLength(n int) int {
+	if o.groupSize <= 0 || n == 0 {
+		return n
+	}
+	return n + (n-1)/o.groupSize*utf8.RuneCountInString(o.groupSep)
+}
+
+// group splits s into chunks of size characters joined by separator; the
+// last chunk may be shorter.
+func group(s string, size int, separator string) string {
+	var b strings.Builder
+	n := 0
+	for _, r := range s {
+		if n > 0 && n%size == 0 {
+			b.WriteString(separator)
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
+
+// EntropyWithOptions calculates the bits of entropy for a passphrase of
+// wordCount words generated with opts, e.g. taking a WithMixedRatio bias into
+// account. It returns 0 if the options are invalid.
+func EntropyWithOptions(wordCount int, opts ...Option) float64 {
+	o := newOptions(opts...)
+	if o.validate() != nil {
+		return 0
+	}
+	return o.breakdown(wordCount).Total
+}
+
+// wordBits returns the entropy of the wordCount drawn words. Without
+// WithUniqueWords each word contributes bitsPerWord. With it, word i is
+// drawn from the effectively 2^bitsPerWord words minus the i already used,
+// which is exact for a single list and a close estimate for mixed ones.
+// WithStartLetters draws the first word from its own, smaller pool.
+func (o *options) wordBits(wordCount int) float64 {
+	if o.alternate != nil {
+		return o.alternatingBits(wordCount)
+	}
+	perWord := o.bitsPerWord()
+	first := perWord
+	if o.startWith != nil {
+		first = drawBits(o.firstSources())
+	}
+	if !o.unique {
+		return first + float64(wordCount-1)*perWord
+	}
+
+	pool := math.Exp2(perWord)
+	bits := first
+	for i := 1; i < wordCount; i++ {
+		if pool-float64(i) < 1 {
+			return 0
+		}
+		bits += math.Log2(pool - float64(i))
+	}
+	return bits
+}
+
+// alternatingBits is wordBits for WithAlternatingLanguages: each word
+// counts the bits of its own language, and with WithUniqueWords its pool
+// shrinks by the words already drawn from the same language.
+func (o *options) alternatingBits(wordCount int) float64 {
+	lists, _ := o.sources()
+	used := make(map[Language]int, len(o.alternate))
+	bits := 0.0
+	for i := 0; i < wordCount; i++ {
+		lang := o.alternate[i%len(o.alternate)]
+		weights := o.slotWeights[i%len(o.alternate)]
+		perWord := drawBits(lists, weights)
+		switch {
+		case i == 0 && o.startWith != nil:
+			first, _ := o.firstSources()
+			bits += drawBits(first, weights)
+		case o.unique:
+			pool := math.Exp2(perWord) - float64(used[lang])
+			if pool < 1 {
+				return 0
+			}
+			bits += math.Log2(pool)
+		default:
+			bits += perWord
+		}
+		used[lang]++
+	}
+	return bits
+}
+
+// poolSize returns the number of usable words across the lists that can be
+// drawn from.
+func (o *options) poolSize() int {
+	lists, weights := o.sources()
+	return pickableSize(lists, weights)
+}
+
+// firstPoolSize is like poolSize for the first word, see firstSources.
+func (o *options) firstPoolSize() int {
+	lists, weights := o.firstSources()
+	return pickableSize(lists, weights)
+}
+
+// pickableSize returns the number of usable words across the lists with a
+// nonzero weight.
+func pickableSize(lists []*Wordlist, weights []float64) int {
+	size := 0
+	for i, wl := range lists {
+		if weights == nil || weights[i] > 0 {
+			size += wl.Size()
+		}
+	}
+	return size
+}
+
+// filtered reports whether any option rules out words of the wordlists.
+func (o *options) filtered() bool {
+	return len(o.blocklist) > 0 || o.minWordLen > 0 || o.wordFilter != nil
+}
+
+// keep reports whether word passes the WithBlocklist, WithMinWordLength
+// and WithWordFilter filters.
+func (o *options) keep(word string) bool {
+	return !o.blocklist[strings.ToLower(word)] && utf8.RuneCountInString(word) >= o.minWordLen &&
+		(o.wordFilter == nil || o.wordFilter(word))
+}
+
+// sources returns the wordlists words are drawn from, with blocklisted and
+// too short words filtered out, and their selection weights (nil meaning
+// uniform). Only valid once the language or wordlists have been validated.
+//
+// With WithAlternatingLanguages the lists are those of every language in
+// turn, and the weights those of a word at a random position; see
+// wordSources for the weights of a given word.
+func (o *options) sources() ([]*Wordlist, []float64) {
+	if o.srcLists != nil {
+		return o.srcLists, o.srcWeights
+	}
+
+	lists, weights := o.wordlists, []float64(nil)
+	switch {
+	case o.alternate != nil:
+		lists, weights = o.alternatingSources()
+	case lists == nil:
+		lists, weights, _ = languageWordlists(o.lang, o.mixedRatio)
+	}
+	if o.filtered() {
+		filtered := make([]*Wordlist, len(lists))
+		for i, wl := range lists {
+			filtered[i] = wl.filter(o.keep)
+		}
+		lists = filtered
+	}
+
+	o.srcLists, o.srcWeights = lists, weights
+	return lists, weights
+}
+
+// alternatingSources concatenates the wordlists of the
+// WithAlternatingLanguages languages and sets slotWeights, which picks
+// from one language's lists only.
+func (o *options) alternatingSources() ([]*Wordlist, []float64) {
+	var lists []*Wordlist
+	var spans [][2]int
+	var inner [][]float64
+	for _, lang := range o.alternate {
+		langLists, langWeights, _ := languageWordlists(lang, o.mixedRatio)
+		spans = append(spans, [2]int{len(lists), len(lists) + len(langLists)})
+		inner = append(inner, langWeights)
+		lists = append(lists, langLists...)
+	}
+
+	k := float64(len(o.alternate))
+	weights := make([]float64, len(lists))
+	o.slotWeights = make([][]float64, len(o.alternate))
+	for slot, span := range spans {
+		o.slotWeights[slot] = make([]float64, len(lists))
+		for i := span[0]; i < span[1]; i++ {
+			w := 1 / float64(span[1]-span[0])
+			if inner[slot] != nil {
+				w = inner[slot][i-span[0]]
+			}
+			o.slotWeights[slot][i] = w
+			weights[i] += w / k
+		}
+	}
+	return lists, weights
+}
+
+// wordSources returns the lists and weights word i (0-based) is drawn
+// from: firstSources for the first word and sources for the rest, with
+// the weights of its WithAlternatingLanguages language.
+func (o *options) wordSources(i int) ([]*Wordlist, []float64) {
+	lists, weights := o.sources()
+	if i == 0 {
+		lists, weights = o.firstSources()
+	}
+	if o.alternate != nil {
+		weights = o.slotWeights[i%len(o.alternate)]
+	}
+	return lists, weights
+}
+
+// firstSources returns the lists the first word is drawn from: the
+// sources, keeping only words WithStartLetters allows.
+func (o *options) firstSources() ([]*Wordlist, []float64) {
+	lists, weights := o.sources()
+	if o.startWith == nil {
+		return lists, weights
+	}
+	if o.firstLists == nil {
+		o.firstLists = make([]*Wordlist, len(lists))
+		for i, wl := range lists {
+			o.firstLists[i] = wl.filter(o.startsRight)
+		}
+	}
+	return o.firstLists, weights
+}
+
+// bitsPerWord returns the Shannon entropy of a single word drawn with these
+// options.
+func (o *options) bitsPerWord() float64 {
+	lists, weights := o.sources()
+	return drawBits(lists, weights)
+}
+
+// drawBits returns the Shannon entropy of a drawWord result for lists and
+// weights.
+func drawBits(lists []*Wordlist, weights []float64) float64 {
+
+	// Each attempt picks list i with probability w_i, then rolls its dice;
+	// attempts landing on an unusable entry are discarded and redone from
+	// scratch. A given usable word of list i therefore comes up with
+	// probability proportional to w_i/6^dice_i (w_i times its own
+	// probability for a weighted list), normalized by the chance an attempt
+	// is accepted at all. With uniform weights this reduces to log2(total
+	// usable words across all lists).
+	weight := func(i int) float64 {
+		if weights == nil {
+			return 1 / float64(len(lists))
+		}
+		return weights[i]
+	}
+
+	accept := acceptRate(lists, weights)
+	if accept == 0 {
+		return 0
+	}
+
+	bits := 0.0
+	for i, wl := range lists {
+		w := weight(i)
+		if w <= 0 || wl.Size() == 0 {
+			continue
+		}
+		if wl.probs == nil {
+			q := w / float64(len(wl.words)) / accept
+			bits -= float64(wl.Size()) * q * math.Log2(q)
+			continue
+		}
+		for j, word := range wl.words {
+			if q := w * wl.probs[j] / accept; word != "" && q > 0 && wl.accepts(word) {
+				bits -= q * math.Log2(q)
+			}
+		}
+	}
+	return bits
+}
+
+// generate is the single generation path behind the public Generate*
+// functions. It validates the word count and options, then returns the
+// joined passphrase along with its capitalized words and the roll and
+// source of each.
+func generate(wordCount int, o *options) (passphrase string, words []string, rolled []RolledWord, err error) {
+	if wordCount < 1 {
+		return "", nil, nil, invalidWordCount(wordCount)
+	}
+	if err := o.validate(); err != nil {
+		return "", nil, nil, err
+	}
+	if o.unique && wordCount > o.poolSize() {
+		return "", nil, nil, fmt.Errorf("can't draw %d unique words from %d usable words", wordCount, o.poolSize())
+	}
+	if o.minEntropy > 0 {
+		if bits := o.breakdown(wordCount).Total; bits < o.minEntropy {
+			return "", nil, nil, insufficientEntropy(wordCount, bits, o.minEntropy)
+		}
+	}
+
+	// Read the randomness for the whole passphrase at once rather than
+	// word by word
+	src := o.rand
+	br := newBatchReader(src, wordCount)
+	if o.randAttempts > 0 {
+		br.attempts = o.randAttempts
+	}
+	o.rand = br
+	defer func() {
+		o.rand = src
+		br.wipe()
+	}()
+
+	if o.minLength == 0 && o.maxLength == 0 {
+		return assemble(wordCount, o)
+	}
+
+	// A transform can change word lengths, so only the attempts below can
+	// tell whether the window is reachable
+	if o.transform == nil {
+		if err := o.checkLengthWindow(wordCount); err != nil {
+			return "", nil, nil, err
+		}
+	}
+	maxAttempts := o.attempts(maxLengthAttempts)
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		passphrase, words, rolled, err = assemble(wordCount, o)
+		if err != nil {
+			return "", nil, nil, err
+		}
+		if o.fitsLength(utf8.RuneCountInString(passphrase)) {
+			return passphrase, words, rolled, nil
+		}
+	}
+	return "", nil, nil, fmt.Errorf("no passphrase of %d words within the length limits after %d attempts: %w", wordCount, maxAttempts, ErrMaxAttempts)
+}
+
+// assemble draws the words with drawWords and joins them, drawing random
+// separators if configured.
+func assemble(wordCount int, o *options) (passphrase string, words []string, rolled []RolledWord, err error) {
+	words, rolled, err = drawWords(wordCount, o)
+	if err != nil {
+		return "", nil, nil, err
+	}
+	gaps, err := o.drawGaps(o.gapCount(len(words)))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	conns, err := o.drawConnectors(len(words))
+	if err != nil {
+		return "", nil, nil, err
+	}
+	return o.join(words, gaps, conns), words, rolled, nil
+}
+
+// applyCase cases drawn word i with the WithCapitalizer function, or else
+// its capitalization mode (see caseMode), flipping a coin from o.rand for
+// CapRandom.
+func (o *options) applyCase(word string, i int) (string, error) {
+	if o.capitalize != nil {
+		return o.capitalize(word), nil
+	}
+	mode := o.caseMode(i)
+	if mode == CapRandom {
+		b, err := readByte(o.rand)
+		if err != nil {
+			return "", randomSourceError(err)
+		}
+		if b&1 == 0 {
+			return strings.ToLower(word), nil
+		}
+		return capitalize(word), nil
+	}
+	return mode.apply(word), nil
+}
+
+// drawWords draws wordCount capitalized words and how each was rolled, plus
+// the WithNumberWord number if configured (see RolledWord).
+func drawWords(wordCount int, o *options) (words []string, rolled []RolledWord, err error) {
+	words = make([]string, wordCount)
+	rolled = make([]RolledWord, wordCount)
+
+	var seen map[string]bool
+	if o.unique {
+		seen = make(map[string]bool, wordCount)
+	}
+
+	for i := 0; i < wordCount; i++ {
+		from, weights := o.wordSources(i)
+		word, roll, list, werr := o.drawDistinct(from, weights, seen)
+		if werr != nil {
+			return nil, nil, fmt.Errorf("failed to generate word %d: %w", i+1, werr)
+		}
+		if words[i], err = o.applyCase(word, i); err != nil {
+			return nil, nil, fmt.Errorf("failed to generate word %d: %w", i+1, err)
+		}
+		if o.transform != nil {
+			words[i] = o.transform(words[i], i)
+		}
+		rolled[i] = RolledWord{Word: words[i], Roll: roll, Lang: list.lang, Wordlist: list.name}
+		if o.observer != nil {
+			o.observer(i, roll, words[i])
+		}
+	}
+
+	if o.numDigits > 0 {
+		return o.insertNumber(words, rolled)
+	}
+	return words, rolled, nil
+}
+
+// drawDistinct draws a word, ASCII-folded if configured. If seen is
+// non-nil (WithUniqueWords), words whose lowercased form is already in seen
+// are redrawn and the accepted word is added to it.
+func (o *options) drawDistinct(lists []*Wordlist, weights []float64, seen map[string]bool) (word, roll string, list *Wordlist, err error) {
+	// Redraws only run out if nearly every word has been used already;
+	// scale the bound like maxDrawAttempts so that stays unlikely.
+	maxAttempts := 1
+	if seen != nil {
+		pool := o.poolSize()
+		maxAttempts = o.attempts(40*pool/(pool-len(seen)+1) + 100)
+	}
+
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		word, roll, list, err = drawWordWithin(o.rand, lists, weights, o.attempts(maxDrawAttempts(lists, weights)))
+		if err == nil && o.asciiFold {
+			word, err = foldASCII(word)
+		}
+		if err != nil {
+			return "", "", nil, err
+		}
+		if seen == nil {
+			return word, roll, list, nil
+		}
+		if key := strings.ToLower(word); !seen[key] {
+			seen[key] = true
+			return word, roll, list, nil
+		}
+	}
+	return "", "", nil, fmt.Errorf("%w: no unused word found after %d attempts: %w", ErrWordNotFound, maxAttempts, ErrMaxAttempts)
+}
+
+// attempts returns the WithMaxAttempts limit if set, or else def.
+func (o *options) attempts(def int) int {
+	if o.maxAttempts > 0 {
+		return o.maxAttempts
+	}
+	return def
+}
+
+// insertNumber inserts a random WithNumberWord number into words at a
+// random boundary where it can't be confused with adjacent digits.
+func (o *options) insertNumber(words []string, rolled []RolledWord) ([]string, []RolledWord, error) {
+	limit := int64(math.Pow10(o.numDigits))
+	n, err := rand.Int(o.rand, big.NewInt(limit))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate number word: %w", randomSourceError(err))
+	}
+	number := fmt.Sprintf("%0*d", o.numDigits, n.Int64())
+
+	var positions []int
+	for i := 0; i <= len(words); i++ {
+		if o.numberFits(words, i) {
+			positions = append(positions, i)
+		}
+	}
+	pos := len(words)
+	if len(positions) > 0 {
+		p, err := rand.Int(o.rand, big.NewInt(int64(len(positions))))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to place number word: %w", randomSourceError(err))
+		}
+		pos = positions[p.Int64()]
+	}
+
+	words = append(words[:pos], append([]string{number}, words[pos:]...)...)
+	rolled = append(rolled[:pos], append([]RolledWord{{Word: number, Lang: LanguageUnknown}}, rolled[pos:]...)...)
+	return words, rolled, nil
+}
+
+// numberFits reports whether a number inserted at index pos of words would
+// be told apart from its neighbors: either a non-empty separator sits
+// between them or the neighbor doesn't touch it with a digit.
+func (o *options) numberFits(words []string, pos int) bool {
+	separator := func(gap int) string {
+		if o.randomSeps != nil {
+			return o.randomSeps[0] // all non-empty
+		}
+		if len(o.separators) == 0 {
+			return ""
+		}
+		if o.leadingSep {
+			gap++
+		}
+		return o.separators[gap%len(o.separators)]
+	}
+	// After insertion, the number is token pos: the gap before it is
+	// pos-1 and the gap after it is pos.
+	if pos > 0 && separator(pos-1) == "" {
+		if r, _ := utf8.DecodeLastRuneInString(words[pos-1]); unicode.IsDigit(r) {
+			return false
+		}
+	}
+	if pos < len(words) && separator(pos) == "" {
+		if r, _ := utf8.DecodeRuneInString(words[pos]); unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// fitsLength reports whether a passphrase of n characters satisfies the
+// WithMinLength/WithMaxLength window.
+func (o *options) fitsLength(n int) bool {
+	return n >= o.minLength && (o.maxLength == 0 || n <= o.maxLength)
+}
+
+// checkLengthWindow returns an error if no passphrase of wordCount words can
+// fit the length window, judging by the shortest and longest usable words
+// and the separators, so generate doesn't spin through rerolls in vain.
+func (o *options) checkLengthWindow(wordCount int) error {
+	lists, weights := o.sources()
+	minWord, maxWord := -1, 0
+	for i, wl := range lists {
+		if wl.Size() == 0 || (weights != nil && weights[i] == 0) {
+			continue
+		}
+		if minWord < 0 || wl.minLen < minWord {
+			minWord = wl.minLen
+		}
+		if wl.maxLen > maxWord {
+			maxWord = wl.maxLen
+		}
+	}
+
+	tokens := wordCount
+	if o.numDigits > 0 {
+		tokens++
+	}
+	minSeps, maxSeps := 0, 0
+	switch {
+	case o.randomSeps != nil:
+		minSep, maxSep := -1, 0
+		for _, sep := range o.randomSeps {
+			n := utf8.RuneCountInString(sep)
+			if minSep < 0 || n < minSep {
+				minSep = n
+			}
+			if n > maxSep {
+				maxSep = n
+			}
+		}
+		minSeps, maxSeps = o.gapCount(tokens)*minSep, o.gapCount(tokens)*maxSep
+	case len(o.separators) > 0:
+		for i := 0; i < o.gapCount(tokens); i++ {
+			minSeps += utf8.RuneCountInString(o.separators[i%len(o.separators)])
+		}
+		maxSeps = minSeps
+	}
+
+	if o.connectors && tokens > 1 {
+		// Each connector comes with a second copy of its gap's separator
+		minConn, maxConn := -1, 0
+		for _, c := range connectorWords {
+			n := utf8.RuneCountInString(c)
+			if minConn < 0 || n < minConn {
+				minConn = n
+			}
+			maxConn = max(maxConn, n)
+		}
+		maxSep := 0
+		for _, sep := range append(slices.Clone(o.separators), o.randomSeps...) {
+			maxSep = max(maxSep, utf8.RuneCountInString(sep))
+		}
+		minSeps += (tokens - 1) * minConn
+		maxSeps += (tokens - 1) * (maxConn + maxSep)
+	}
+
+	affixes := utf8.RuneCountInString(o.prefix) + utf8.RuneCountInString(o.suffix)
+	shortest := o.groupedLength(wordCount*minWord+o.numDigits+minSeps) + affixes
+	longest := o.groupedLength(wordCount*maxWord+o.numDigits+maxSeps) + affixes
+	if longest < o.minLength || (o.maxLength > 0 && shortest > o.maxLength) {
+		return fmt.Errorf("a %d-word passphrase is %d to %d characters long, which can't satisfy the length limits (min %d, max %d)",
+			wordCount, shortest, longest, o.minLength, o.maxLength)
+	}
+	return nil
+}
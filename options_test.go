@@ -0,0 +1,806 @@
+package diceware
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// wordSet returns the lowercased words of a parsed wordlist as a set, for
+// checking which list a generated word came from.
+func wordSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, word := range list {
+		if word != "" {
+			set[strings.ToLower(word)] = true
+		}
+	}
+	return set
+}
+
+func TestGenerateWithOptions(t *testing.T) {
+	tests := []struct {
+		name      string
+		wordCount int
+		opts      []Option
+		wantErr   bool
+	}{
+		{"defaults", 6, nil, false},
+		{"separator", 4, []Option{WithSeparator("-")}, false},
+		{"Romanian", 4, []Option{WithLanguage(LanguageRomanian)}, false},
+		{"mixed with ratio", 4, []Option{WithLanguage(LanguageMixed), WithMixedRatio(0.75)}, false},
+		{"invalid word count", 0, nil, true},
+		{"unsupported language", 4, []Option{WithLanguage(Language(99))}, true},
+		{"ratio below 0", 4, []Option{WithLanguage(LanguageMixed), WithMixedRatio(-0.1)}, true},
+		{"ratio above 1", 4, []Option{WithLanguage(LanguageMixed), WithMixedRatio(1.5)}, true},
+		{"ratio NaN", 4, []Option{WithLanguage(LanguageMixed), WithMixedRatio(math.NaN())}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passphrase, err := GenerateWithOptions(tt.wordCount, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GenerateWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && passphrase == "" {
+				t.Error("GenerateWithOptions() returned empty passphrase")
+			}
+		})
+	}
+}
+
+// TestMixedRatioExtremes checks that ratios of 1 and 0 draw exclusively from
+// the English and Romanian wordlists respectively
+func TestMixedRatioExtremes(t *testing.T) {
+	tests := []struct {
+		name  string
+		ratio float64
+		list  []string
+	}{
+		{"all English", 1, builtin(LanguageEnglish).words},
+		{"all Romanian", 0, builtin(LanguageRomanian).words},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set := wordSet(tt.list)
+			passphrase, err := GenerateWithOptions(20,
+				WithLanguage(LanguageMixed), WithMixedRatio(tt.ratio), WithSeparator(" "))
+			if err != nil {
+				t.Fatalf("GenerateWithOptions() error = %v", err)
+			}
+			for _, word := range strings.Split(passphrase, " ") {
+				if !set[strings.ToLower(word)] {
+					t.Errorf("word %q is not from the expected wordlist", word)
+				}
+			}
+		})
+	}
+}
+
+func TestWithAlternatingLanguages(t *testing.T) {
+	langs := []Language{LanguageEnglish, LanguageRomanian, LanguageRomanian}
+	for i := 0; i < 20; i++ {
+		_, words, err := GenerateWithRolledWords(7, WithAlternatingLanguages(langs))
+		if err != nil {
+			t.Fatalf("GenerateWithRolledWords() error = %v", err)
+		}
+		for j, w := range words {
+			if want := langs[j%len(langs)]; w.Lang != want {
+				t.Fatalf("word %d %q is from %v, want %v", j, w.Word, w.Lang, want)
+			}
+		}
+	}
+
+	// The fixed sequence adds no bits: each word counts its own language
+	en, ro := EntropyForLanguage(1, LanguageEnglish), EntropyForLanguage(1, LanguageRomanian)
+	tests := []struct {
+		opts []Option
+		want float64
+	}{
+		{[]Option{WithAlternatingLanguages(langs)}, 3*en + 4*ro},
+		{[]Option{WithAlternatingLanguages([]Language{LanguageEnglish})}, Entropy(7)},
+		{[]Option{WithAlternatingLanguages([]Language{LanguageMixed, LanguageEnglish})}, 4*EntropyForLanguage(1, LanguageMixed) + 3*en},
+		{[]Option{WithAlternatingLanguages(langs), WithLanguage(LanguageRomanian)}, 7 * ro},
+		{[]Option{WithAlternatingLanguages(langs), WithUniqueWords(true)}, math.Log2(7776*7775*7774) + math.Log2(math.Exp2(ro)*(math.Exp2(ro)-1)*(math.Exp2(ro)-2)*(math.Exp2(ro)-3))},
+	}
+	for i, tt := range tests {
+		if got := EntropyWithOptions(7, tt.opts...); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("case %d: EntropyWithOptions() = %f, want %f", i, got, tt.want)
+		}
+	}
+
+	for _, bad := range [][]Language{{}, {LanguageEnglish, Language(99)}} {
+		if _, err := GenerateWithOptions(4, WithAlternatingLanguages(bad)); err == nil {
+			t.Errorf("WithAlternatingLanguages(%v) should return an error", bad)
+		}
+	}
+}
+
+func TestEntropyWithOptions(t *testing.T) {
+	const wordCount = 6
+	mixed := func(ratio float64) float64 {
+		return EntropyWithOptions(wordCount, WithLanguage(LanguageMixed), WithMixedRatio(ratio))
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+
+	if got, want := EntropyWithOptions(wordCount), Entropy(wordCount); !near(got, want) {
+		t.Errorf("EntropyWithOptions() default = %f, want %f", got, want)
+	}
+	if got, want := mixed(0.5), EntropyForLanguage(wordCount, LanguageMixed); !near(got, want) {
+		t.Errorf("fair ratio entropy = %f, want %f (EntropyForLanguage)", got, want)
+	}
+	if got, want := mixed(1), EntropyForLanguage(wordCount, LanguageEnglish); !near(got, want) {
+		t.Errorf("ratio 1 entropy = %f, want English %f", got, want)
+	}
+	if got, want := mixed(0), EntropyForLanguage(wordCount, LanguageRomanian); !near(got, want) {
+		t.Errorf("ratio 0 entropy = %f, want Romanian %f", got, want)
+	}
+
+	// A biased ratio still mixes two lists, so it beats either list alone,
+	// but loses some of the fair coin flip's extra bit.
+	biased := mixed(0.75)
+	if biased >= mixed(0.5) || biased <= EntropyForLanguage(wordCount, LanguageEnglish) {
+		t.Errorf("ratio 0.75 entropy = %f, want between English %f and fair mixed %f",
+			biased, EntropyForLanguage(wordCount, LanguageEnglish), mixed(0.5))
+	}
+
+	if got := EntropyWithOptions(wordCount, WithMixedRatio(2)); got != 0 {
+		t.Errorf("EntropyWithOptions() with invalid ratio = %f, want 0", got)
+	}
+}
+
+func TestWithSeparators(t *testing.T) {
+	words := []string{"Colt", "Default", "Arousal", "Thimble"}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"default", nil, "ColtDefaultArousalThimble"},
+		{"single separator", []Option{WithSeparator("-")}, "Colt-Default-Arousal-Thimble"},
+		{"single-element slice", []Option{WithSeparators([]string{"-"})}, "Colt-Default-Arousal-Thimble"},
+		{"alternating", []Option{WithSeparators([]string{"-", "_"})}, "Colt-Default_Arousal-Thimble"},
+		{"longer than gaps", []Option{WithSeparators([]string{"1", "2", "3", "4", "5"})}, "Colt1Default2Arousal3Thimble"},
+		{"empty slice", []Option{WithSeparators(nil)}, "ColtDefaultArousalThimble"},
+		{"later option wins", []Option{WithSeparators([]string{"-", "_"}), WithSeparator(" ")}, "Colt Default Arousal Thimble"},
+		{"grouped", []Option{WithGrouping(4, "-")}, "Colt-Defa-ultA-rous-alTh-imbl-e"},
+		{"grouped with separator", []Option{WithSeparator(" "), WithGrouping(5, "|")}, "Colt |Defau|lt Ar|ousal| Thim|ble"},
+		{"leading", []Option{WithSeparator("-"), WithLeadingSeparator(true)}, "-Colt-Default-Arousal-Thimble"},
+		{"trailing", []Option{WithSeparator("-"), WithTrailingSeparator(true)}, "Colt-Default-Arousal-Thimble-"},
+		{"both", []Option{WithSeparator("."), WithLeadingSeparator(true), WithTrailingSeparator(true)}, ".Colt.Default.Arousal.Thimble."},
+		{"leading and trailing in turn", []Option{WithSeparators([]string{"1", "2"}), WithLeadingSeparator(true), WithTrailingSeparator(true)}, "1Colt2Default1Arousal2Thimble1"},
+		{"no separator to add", []Option{WithLeadingSeparator(true), WithTrailingSeparator(true)}, "ColtDefaultArousalThimble"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newOptions(tt.opts...).join(words, nil, nil); got != tt.want {
+				t.Errorf("join() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	passphrase, err := GenerateWithOptions(5, WithSeparators([]string{"-", "_"}))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	if strings.Count(passphrase, "_") < 2 {
+		t.Errorf("GenerateWithOptions() = %q, want alternating separators", passphrase)
+	}
+}
+
+func TestLengthLimits(t *testing.T) {
+	tests := []struct {
+		name      string
+		wordCount int
+		opts      []Option
+		wantErr   bool
+	}{
+		{"min only", 4, []Option{WithMinLength(30)}, false},
+		{"max only", 6, []Option{WithMaxLength(40), WithSeparator("-")}, false},
+		{"window", 5, []Option{WithMinLength(25), WithMaxLength(35), WithSeparator(" ")}, false},
+		{"negative", 4, []Option{WithMinLength(-1)}, true},
+		{"min above max", 4, []Option{WithMinLength(50), WithMaxLength(40)}, true},
+		{"max too short", 6, []Option{WithMaxLength(10)}, true},
+		{"min too long", 2, []Option{WithMinLength(100)}, true},
+		{"separators count", 4, []Option{WithSeparator("--"), WithMaxLength(17)}, true},
+		{"grouping counts", 4, []Option{WithGrouping(2, "-"), WithMaxLength(16)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := newOptions(tt.opts...)
+			for i := 0; i < 20; i++ {
+				passphrase, err := GenerateWithOptions(tt.wordCount, tt.opts...)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GenerateWithOptions() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if err != nil {
+					return
+				}
+				if n := len([]rune(passphrase)); !o.fitsLength(n) {
+					t.Errorf("GenerateWithOptions() = %q (%d characters), outside [%d, %d]",
+						passphrase, n, o.minLength, o.maxLength)
+				}
+			}
+		})
+	}
+}
+
+func TestWithNumberWord(t *testing.T) {
+	for digits := 1; digits <= 4; digits++ {
+		_, words, _, err := generate(4, newOptions(WithNumberWord(digits)))
+		if err != nil {
+			t.Fatalf("generate() with %d digits error = %v", digits, err)
+		}
+		if len(words) != 5 {
+			t.Fatalf("generate() returned %d tokens, want 5", len(words))
+		}
+		numbers := 0
+		for _, w := range words {
+			if w[0] >= '0' && w[0] <= '9' {
+				numbers++
+				if len(w) != digits {
+					t.Errorf("number word %q has %d digits, want %d", w, len(w), digits)
+				}
+			}
+		}
+		if numbers != 1 {
+			t.Errorf("generate() = %v, want exactly one number word", words)
+		}
+
+		want := Entropy(4) + float64(digits)*math.Log2(10)
+		if got := EntropyWithOptions(4, WithNumberWord(digits)); math.Abs(got-want) > 1e-9 {
+			t.Errorf("EntropyWithOptions() with %d digits = %f, want %f", digits, got, want)
+		}
+	}
+
+	for _, digits := range []int{-1, 5} {
+		if _, err := GenerateWithOptions(4, WithNumberWord(digits)); err == nil {
+			t.Errorf("WithNumberWord(%d) should return an error", digits)
+		}
+	}
+}
+
+// TestNumberWordPlacement checks that without a separator the number is
+// never placed against a word that touches it with a digit
+func TestNumberWordPlacement(t *testing.T) {
+	o := newOptions(WithNumberWord(2))
+	words := []string{"Alpha", "Beta7", "9gamma"}
+
+	for pos, want := range []bool{true, true, false, true} {
+		if got := o.numberFits(words, pos); got != want {
+			t.Errorf("numberFits(%d) = %v, want %v", pos, got, want)
+		}
+	}
+	if !newOptions(WithSeparator("-")).numberFits(words, 2) {
+		t.Error("numberFits() with a separator should allow every position")
+	}
+}
+
+func TestWithUniqueWords(t *testing.T) {
+	small := testWordlist(t, "small", 5)
+	for i := 0; i < 5; i++ {
+		_, words, _, err := generate(5, newOptions(WithWordlists(small), WithUniqueWords(true)))
+		if err != nil {
+			t.Fatalf("generate() error = %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, w := range words {
+			if seen[w] {
+				t.Fatalf("generate() = %v, repeats %q", words, w)
+			}
+			seen[w] = true
+		}
+	}
+	if _, err := GenerateWithOptions(6, WithWordlists(small), WithUniqueWords(true)); err == nil {
+		t.Error("GenerateWithOptions() with more unique words than the list has should return an error")
+	}
+
+	// A word shared by two lists counts once, regardless of case
+	a, _ := NewWordlist("a", map[string]string{"11111": "Abator", "11112": "alpha"})
+	b, _ := NewWordlist("b", map[string]string{"11111": "abator", "11112": "beta"})
+	for i := 0; i < 5; i++ {
+		_, words, _, err := generate(3, newOptions(WithWordlists(a, b), WithUniqueWords(true)))
+		if err != nil {
+			t.Fatalf("generate() error = %v", err)
+		}
+		seen := make(map[string]bool)
+		for _, w := range words {
+			if key := strings.ToLower(w); seen[key] {
+				t.Fatalf("generate() = %v, repeats %q across lists", words, w)
+			} else {
+				seen[key] = true
+			}
+		}
+	}
+}
+
+func TestUniqueWordsEntropy(t *testing.T) {
+	custom := testWordlist(t, "custom", 1000)
+	got := EntropyWithOptions(4, WithWordlists(custom), WithUniqueWords(true))
+	want := math.Log2(1000 * 999 * 998 * 997)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() with unique words = %f, want %f", got, want)
+	}
+	if full := EntropyWithOptions(4, WithWordlists(custom)); got >= full {
+		t.Errorf("unique entropy %f should be below %f", got, full)
+	}
+}
+
+func TestWithGrouping(t *testing.T) {
+	if got := group("ȚarăȘarpe", 3, " "); got != "Țar ăȘa rpe" {
+		t.Errorf("group() = %q, want %q", got, "Țar ăȘa rpe")
+	}
+
+	// Not "-", which some EFF words contain
+	passphrase, err := GenerateWithOptions(6, WithGrouping(4, " "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	chunks := strings.Split(passphrase, " ")
+	for i, chunk := range chunks[:len(chunks)-1] {
+		if len(chunk) != 4 {
+			t.Errorf("chunk %d = %q, want 4 characters", i, chunk)
+		}
+	}
+	if got, want := EntropyWithOptions(6, WithGrouping(4, "-")), Entropy(6); got != want {
+		t.Errorf("EntropyWithOptions() with grouping = %f, want %f", got, want)
+	}
+	if _, err := GenerateWithOptions(6, WithGrouping(-1, "-")); err == nil {
+		t.Error("WithGrouping(-1) should return an error")
+	}
+}
+
+func TestWithPrefixAndSuffix(t *testing.T) {
+	custom, err := NewWordlist("custom", map[string]string{"1": "alpha", "2": "beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := []Option{WithWordlists(custom), WithSeparator("-"), WithPrefix("ACME-"), WithSuffix("!")}
+	passphrase, words, _, err := generate(3, newOptions(opts...))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	if want := "ACME-" + strings.Join(words, "-") + "!"; passphrase != want {
+		t.Errorf("generate() = %q, want %q", passphrase, want)
+	}
+
+	// The affixes aren't grouped, and the length limits count them
+	grouped, err := GenerateWithOptions(2, WithWordlists(custom), WithGrouping(2, " "), WithPrefix("X-"), WithMinLength(16))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	if want := "X-Al ph aA lp ha"; grouped != want {
+		t.Errorf("GenerateWithOptions() with grouping = %q, want %q", grouped, want)
+	}
+	if _, err := GenerateWithOptions(3, WithWordlists(custom), WithPrefix("ACME-"), WithMaxLength(16)); err == nil {
+		t.Error("GenerateWithOptions() should return an error when the prefix leaves no room for the words")
+	}
+
+	if got, want := EntropyWithOptions(3, opts...), EntropyWithOptions(3, opts[:2]...); got != want {
+		t.Errorf("EntropyWithOptions() with affixes = %f, want %f", got, want)
+	}
+}
+
+func TestWithBlocklist(t *testing.T) {
+	custom, err := NewWordlist("custom", map[string]string{
+		"11111": "alpha", "11112": "beta", "11113": "gamma", "11114": "delta",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blocklist := []string{"Beta", " GAMMA "}
+
+	passphrase, err := GenerateWithOptions(20, WithWordlists(custom), WithBlocklist(blocklist), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	for _, word := range strings.Split(passphrase, " ") {
+		if word != "Alpha" && word != "Delta" {
+			t.Errorf("word %q should have been blocked", word)
+		}
+	}
+
+	if got, want := EntropyWithOptions(3, WithWordlists(custom), WithBlocklist(blocklist)), 3.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() with blocklist = %f, want %f (2 words left)", got, want)
+	}
+	// The blocklist only applies to the options it was given to
+	if custom.Size() != 4 {
+		t.Errorf("blocklist modified the wordlist: Size() = %d, want 4", custom.Size())
+	}
+
+	if _, err := GenerateWithOptions(3, WithWordlists(custom), WithBlocklist(blocklist), WithUniqueWords(true)); err == nil {
+		t.Error("GenerateWithOptions() should fail when the blocklist leaves too few unique words")
+	}
+	all := []string{"alpha", "beta", "gamma", "delta"}
+	if _, err := GenerateWithOptions(1, WithWordlists(custom), WithBlocklist(all)); err == nil {
+		t.Error("GenerateWithOptions() should fail when the blocklist excludes every word")
+	}
+
+	// Built-in languages work too
+	_, words, _, err := generate(50, newOptions(WithBlocklist([]string{"abacus"})))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for _, w := range words {
+		if w == "Abacus" {
+			t.Error("blocked word Abacus was generated")
+		}
+	}
+	if got, want := EntropyWithOptions(1, WithBlocklist([]string{"abacus"})), math.Log2(7775); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, want)
+	}
+}
+
+func TestWithConnectorWords(t *testing.T) {
+	isConnector := make(map[string]bool)
+	for _, c := range connectorWords {
+		isConnector[c] = true
+	}
+	used := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		p, err := GeneratePassphrase(4, WithConnectorWords(true), WithSeparator(" "))
+		if err != nil {
+			t.Fatalf("GeneratePassphrase() error = %v", err)
+		}
+		tokens := strings.Split(p.String(), " ")
+		words := p.Words()
+		if len(words) != 4 || len(tokens) != 7 {
+			t.Fatalf("passphrase %q with words %q, want 4 words and 3 connectors", p, words)
+		}
+		for j, token := range tokens {
+			switch {
+			case j%2 == 0 && token != words[j/2]:
+				t.Fatalf("passphrase %q has %q where word %q should be", p, token, words[j/2])
+			case j%2 == 1 && !isConnector[token]:
+				t.Fatalf("passphrase %q has %q where a connector should be", p, token)
+			}
+			if j%2 == 1 {
+				used[token] = true
+			}
+		}
+	}
+	if len(used) < 2 {
+		t.Errorf("only connectors %v were used in 60 gaps", used)
+	}
+
+	// Each connector adds log2(16) = 4 bits, the number word adding one
+	// more gap
+	tests := []struct {
+		opts []Option
+		want float64
+	}{
+		{[]Option{WithConnectorWords(true)}, Entropy(6) + 5*4},
+		{[]Option{WithConnectorWords(true), WithNumberWord(2)}, Entropy(6) + 6*4 + 2*math.Log2(10)},
+		{[]Option{WithConnectorWords(false)}, Entropy(6)},
+	}
+	for i, tt := range tests {
+		if got := EntropyWithOptions(6, tt.opts...); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("case %d: EntropyWithOptions() = %f, want %f", i, got, tt.want)
+		}
+	}
+
+	// The connectors count towards the length window
+	wl, _ := NewWordlist("test", map[string]string{"11111": "ab"})
+	got, err := GenerateWithOptions(2, WithWordlists(wl), WithConnectorWords(true), WithSeparator("-"), WithMinLength(10))
+	if err != nil || len(got) != 10 {
+		t.Errorf("GenerateWithOptions() = %q, %v, want 10 characters, e.g. Ab-from-Ab", got, err)
+	}
+	if _, err := GenerateWithOptions(2, WithWordlists(wl), WithConnectorWords(true), WithSeparator("-"), WithMinLength(11)); err == nil {
+		t.Error("GenerateWithOptions() should reject a minimum length above the longest possible passphrase")
+	}
+}
+
+func TestWithSeparatorRandom(t *testing.T) {
+	// No "-": some EFF words contain one, like "T-shirt", so it is
+	// treated as part of the words below
+	set := []string{"=", "_", ".", "+"}
+	used := make(map[string]bool)
+	for i := 0; i < 20; i++ {
+		passphrase, err := GenerateWithOptions(6, WithSeparatorRandom(set))
+		if err != nil {
+			t.Fatalf("GenerateWithOptions() error = %v", err)
+		}
+		seps := strings.FieldsFunc(passphrase, func(r rune) bool {
+			return unicode.IsLetter(r) || r == '-'
+		})
+		if len(seps) != 5 {
+			t.Fatalf("passphrase %q has separators %q, want 5 between the words only", passphrase, seps)
+		}
+		for _, sep := range seps {
+			if !strings.Contains("=_.+", sep) || len(sep) != 1 {
+				t.Fatalf("passphrase %q has unexpected separator %q", passphrase, sep)
+			}
+			used[sep] = true
+		}
+	}
+	if len(used) < 2 {
+		t.Errorf("only separators %v were used in 100 gaps", used)
+	}
+
+	// Each gap adds log2(4) = 2 bits, the number word adding one more gap
+	tests := []struct {
+		opts []Option
+		want float64
+	}{
+		{[]Option{WithSeparatorRandom(set)}, Entropy(6) + 5*2},
+		{[]Option{WithSeparatorRandom(set), WithNumberWord(2)}, Entropy(6) + 6*2 + 2*math.Log2(10)},
+		{[]Option{WithSeparatorRandom([]string{" "})}, Entropy(6)},
+		{[]Option{WithSeparatorRandom(set), WithSeparator("-")}, Entropy(6)},
+	}
+	for i, tt := range tests {
+		if got := EntropyWithOptions(6, tt.opts...); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("case %d: EntropyWithOptions() = %f, want %f", i, got, tt.want)
+		}
+	}
+
+	// The length window accounts for the longest and shortest separator
+	wl, _ := NewWordlist("test", map[string]string{"11111": "ab"})
+	if _, err := GenerateWithOptions(3, WithWordlists(wl), WithSeparatorRandom([]string{"-", "=="}), WithMinLength(11)); err == nil {
+		t.Error("GenerateWithOptions() should reject a minimum length above the longest possible passphrase")
+	}
+	got, err := GenerateWithOptions(3, WithWordlists(wl), WithSeparatorRandom([]string{"-", "=="}), WithMinLength(10))
+	if err != nil || got != "Ab==Ab==Ab" {
+		t.Errorf("GenerateWithOptions() = %q, %v, want %q", got, err, "Ab==Ab==Ab")
+	}
+
+	// Leading and trailing separators are drawn and counted like the others
+	got, err = GenerateWithOptions(3, WithWordlists(wl), WithSeparatorRandom([]string{"-", "=="}), WithTrailingSeparator(true), WithMinLength(12))
+	if err != nil || got != "Ab==Ab==Ab==" {
+		t.Errorf("GenerateWithOptions() = %q, %v, want %q", got, err, "Ab==Ab==Ab==")
+	}
+	opts := []Option{WithSeparatorRandom(set), WithLeadingSeparator(true), WithTrailingSeparator(true)}
+	if got, want := EntropyWithOptions(6, opts...), Entropy(6)+7*2; math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() with leading and trailing separators = %f, want %f", got, want)
+	}
+
+	for _, bad := range [][]string{nil, {}, {"-", ""}, {"-", "-"}} {
+		if _, err := GenerateWithOptions(3, WithSeparatorRandom(bad)); err == nil {
+			t.Errorf("WithSeparatorRandom(%q) should return an error", bad)
+		}
+	}
+}
+
+func TestWithRollObserver(t *testing.T) {
+	var indexes []int
+	var observed []string
+	_, words, err := GenerateWithRolledWords(5, WithRollObserver(func(i int, roll, word string) {
+		indexes = append(indexes, i)
+		observed = append(observed, roll+" "+word)
+	}), WithLanguage(LanguageRomanian), WithSeparator("-"))
+	if err != nil {
+		t.Fatalf("GenerateWithRolledWords() error = %v", err)
+	}
+	if len(observed) != len(words) {
+		t.Fatalf("observer called %d times, want %d", len(observed), len(words))
+	}
+	for i, w := range words {
+		if indexes[i] != i || observed[i] != w.Roll+" "+w.Word {
+			t.Errorf("observation %d = %d %q, want %d %q", i, indexes[i], observed[i], i, w.Roll+" "+w.Word)
+		}
+	}
+}
+
+func TestWithMaxAttempts(t *testing.T) {
+	// A transform can push every passphrase out of the length window
+	long := WithWordTransform(func(word string, _ int) string { return word + "xxxxxx" })
+	_, err := GenerateWithOptions(3, long, WithMaxLength(20), WithMaxAttempts(3))
+	if !errors.Is(err, ErrMaxAttempts) || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("GenerateWithOptions() error = %v, want ErrMaxAttempts after 3 attempts", err)
+	}
+
+	// A single roll out of 36 is usable, so a single attempt per word
+	// fails for all but about one in 36^6 passphrases
+	wl, err := NewWordlist("sparse", map[string]string{"11": "lone"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = GenerateWithOptions(6, WithWordlists(wl), WithMaxAttempts(1))
+	if !errors.Is(err, ErrMaxAttempts) || !errors.Is(err, ErrWordNotFound) || !strings.Contains(err.Error(), "after 1 attempts") {
+		t.Errorf("GenerateWithOptions() error = %v, want ErrMaxAttempts after 1 attempts", err)
+	}
+
+	// Satisfiable settings still succeed with the limit
+	if _, err := GenerateWithOptions(6, WithMaxAttempts(1)); err != nil {
+		t.Errorf("GenerateWithOptions() error = %v", err)
+	}
+	if _, err := GenerateWithOptions(6, WithMaxAttempts(-1)); err == nil {
+		t.Error("WithMaxAttempts(-1) should return an error")
+	}
+}
+
+func TestRequireMinEntropy(t *testing.T) {
+	_, err := GenerateWithOptions(3, RequireMinEntropy(78))
+	if !errors.Is(err, ErrInsufficientEntropy) {
+		t.Errorf("3 words with RequireMinEntropy(78) error = %v, want ErrInsufficientEntropy", err)
+	}
+	if _, err := GenerateWithOptions(7, RequireMinEntropy(78)); err != nil {
+		t.Errorf("7 words with RequireMinEntropy(78) error = %v", err)
+	}
+
+	// Decorations count towards the requirement
+	if _, err := GenerateWithOptions(6, RequireMinEntropy(80), WithNumberWord(2)); err != nil {
+		t.Errorf("6 words and a number with RequireMinEntropy(80) error = %v", err)
+	}
+
+	for _, bits := range []float64{-1, math.NaN()} {
+		if _, err := GenerateWithOptions(6, RequireMinEntropy(bits)); err == nil {
+			t.Errorf("RequireMinEntropy(%v) should return an error", bits)
+		}
+	}
+}
+
+func TestWithMinWordLength(t *testing.T) {
+	custom, err := NewWordlist("custom", map[string]string{
+		"11111": "ad", "11112": "an", "11113": "gamma", "11114": "delta",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	passphrase, err := GenerateWithOptions(20, WithWordlists(custom), WithMinWordLength(3), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	for _, word := range strings.Split(passphrase, " ") {
+		if len(word) < 3 {
+			t.Errorf("word %q is shorter than 3 characters", word)
+		}
+	}
+	if got, want := EntropyWithOptions(3, WithWordlists(custom), WithMinWordLength(3)), 3.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() = %f, want %f (2 words left)", got, want)
+	}
+
+	// Combines with the blocklist
+	if got, _ := GenerateWithOptions(1, WithWordlists(custom), WithMinWordLength(3), WithBlocklist([]string{"gamma"})); got != "Delta" {
+		t.Errorf("GenerateWithOptions() = %q, want %q", got, "Delta")
+	}
+
+	for _, n := range []int{-1, 6} {
+		if _, err := GenerateWithOptions(1, WithWordlists(custom), WithMinWordLength(n)); err == nil {
+			t.Errorf("WithMinWordLength(%d) should return an error", n)
+		}
+	}
+
+	// Built-in languages: the EFF list has words of 3 to 9 letters
+	if EntropyWithOptions(1, WithMinWordLength(4)) >= Entropy(1) {
+		t.Error("WithMinWordLength(4) should lower the English entropy")
+	}
+	if EntropyWithOptions(1, WithMinWordLength(3)) != Entropy(1) {
+		t.Error("WithMinWordLength(3) shouldn't exclude any English word")
+	}
+}
+
+func TestWithWordFilter(t *testing.T) {
+	animals := map[string]bool{"otter": true, "panda": true, "walrus": true, "lynx": true}
+	isAnimal := func(word string) bool { return animals[word] }
+
+	passphrase, err := GenerateWithOptions(20, WithWordFilter(isAnimal), WithCapitalization(CapNone), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	for _, word := range strings.Split(passphrase, " ") {
+		if !animals[word] {
+			t.Errorf("word %q isn't an animal", word)
+		}
+	}
+	// 3 of the 4 are in the EFF list; "lynx" isn't
+	if got, want := EntropyWithOptions(2, WithWordFilter(isAnimal)), 2*math.Log2(3); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() = %f, want %f (3 words left)", got, want)
+	}
+
+	// Combines with the other filters
+	if got, _ := GenerateWithOptions(1, WithWordFilter(isAnimal), WithMinWordLength(6)); got != "Walrus" {
+		t.Errorf("GenerateWithOptions() = %q, want %q", got, "Walrus")
+	}
+
+	if _, err := GenerateWithOptions(1, WithWordFilter(func(string) bool { return false })); err == nil {
+		t.Error("a filter rejecting every word should return an error")
+	}
+	if _, err := GenerateWithOptions(4, WithWordFilter(isAnimal), WithUniqueWords(true)); err == nil {
+		t.Error("WithUniqueWords should return an error with fewer filtered words than requested")
+	}
+	if got := EntropyWithOptions(1, WithWordFilter(nil)); got != Entropy(1) {
+		t.Errorf("EntropyWithOptions() with a nil filter = %f, want %f", got, Entropy(1))
+	}
+}
+
+func TestWithStartLetters(t *testing.T) {
+	custom, err := NewWordlist("custom", map[string]string{
+		"11111": "alpha", "11112": "apple", "11113": "beta", "11114": "gamma",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 20; i++ {
+		passphrase, err := GenerateWithOptions(3, WithWordlists(custom), WithStartLetters([]rune("A")), WithSeparator(" "))
+		if err != nil {
+			t.Fatalf("GenerateWithOptions() error = %v", err)
+		}
+		if first := strings.Fields(passphrase)[0]; first != "Alpha" && first != "Apple" {
+			t.Fatalf("first word %q doesn't start with a", first)
+		}
+	}
+	// 1 bit for the first word, 2 for each of the others
+	if got, want := EntropyWithOptions(3, WithWordlists(custom), WithStartLetters([]rune("a"))), 5.0; math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, want)
+	}
+	if got, want := EntropyWithOptions(3, WithWordlists(custom), WithStartLetters([]rune("ab")), WithUniqueWords(true)), math.Log2(3*3*2); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() with unique words = %f, want %f", got, want)
+	}
+	if EntropyWithOptions(6, WithStartLetters(nil)) != Entropy(6) {
+		t.Error("WithStartLetters(nil) should not constrain anything")
+	}
+
+	// Impossible constraints fail instead of rerolling forever
+	if _, err := GenerateWithOptions(3, WithWordlists(custom), WithStartLetters([]rune("xz"))); err == nil || !strings.Contains(err.Error(), `"xz"`) {
+		t.Errorf("impossible start letters error = %v, want one naming the letters", err)
+	}
+	if _, err := GenerateWithOptions(3, WithWordlists(custom), WithStartLetters([]rune("g")), WithBlocklist([]string{"gamma"})); err == nil {
+		t.Error("start letters only matching blocklisted words should return an error")
+	}
+
+	// Mixed keeps the matching words of both lists
+	passphrase, err := GenerateWithOptions(1, WithLanguage(LanguageMixed), WithStartLetters([]rune("Z")))
+	if err != nil || !strings.HasPrefix(passphrase, "Z") {
+		t.Errorf("GenerateWithOptions() = %q, %v, want a word starting with Z", passphrase, err)
+	}
+	if got := EntropyWithOptions(1, WithLanguage(LanguageMixed), WithStartLetters([]rune("z"))); got <= 0 || got >= EntropyForLanguage(1, LanguageMixed) {
+		t.Errorf("EntropyWithOptions() = %f, want less than an unconstrained word", got)
+	}
+}
+
+func TestWithCapitalizer(t *testing.T) {
+	_, words, _, err := generate(4, newOptions(WithCapitalizer(strings.ToUpper)))
+	if err != nil {
+		t.Fatalf("generate() error = %v", err)
+	}
+	for _, w := range words {
+		if w != strings.ToUpper(w) {
+			t.Errorf("word %q was not upper-cased by the custom capitalizer", w)
+		}
+	}
+
+	turkish, _ := NewWordlist("tr", map[string]string{"11111": "istanbul"})
+	got, err := GenerateWithOptions(1, WithWordlists(turkish), WithCapitalizer(func(word string) string {
+		return strings.ToUpperSpecial(unicode.TurkishCase, word[:1]) + word[1:]
+	}))
+	if err != nil || got != "İstanbul" {
+		t.Errorf("GenerateWithOptions() with Turkish casing = %q, %v, want %q", got, err, "İstanbul")
+	}
+
+	if got, _ := GenerateWithOptions(1, WithWordlists(turkish), WithCapitalizer(nil)); got != "Istanbul" {
+		t.Errorf("WithCapitalizer(nil) = %q, want the default %q", got, "Istanbul")
+	}
+}
+
+func TestWithWordTransform(t *testing.T) {
+	wl, _ := NewWordlist("test", map[string]string{"11111": "alpha"})
+	truncate := WithWordTransform(func(word string, index int) string {
+		return fmt.Sprintf("%s%d", word[:2], index)
+	})
+
+	got, err := GenerateWithOptions(3, WithWordlists(wl), WithSeparator("-"), truncate)
+	if err != nil || got != "Al0-Al1-Al2" {
+		t.Errorf("GenerateWithOptions() = %q, %v, want %q", got, err, "Al0-Al1-Al2")
+	}
+
+	// The number word is left alone, and the transformed lengths are what
+	// the length limits check
+	got, err = GenerateWithOptions(2, WithWordlists(wl), WithNumberWord(2), WithMaxLength(8), truncate)
+	if err != nil || utf8.RuneCountInString(got) != 8 {
+		t.Errorf("GenerateWithOptions() with number and max length = %q, %v, want 8 characters", got, err)
+	}
+
+	if got, want := EntropyWithOptions(3, truncate), EntropyWithOptions(3); got != want {
+		t.Errorf("EntropyWithOptions() with a transform = %f, want %f", got, want)
+	}
+}
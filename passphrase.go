@@ -0,0 +1,119 @@
+package diceware
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+)
+
+// Passphrase is a generated passphrase that keeps its structure: the
+// words, how they were joined, the language and the entropy, so UIs and
+// JSON output can show them without splitting the string back apart. Use
+// GeneratePassphrase to create one; the plain-string functions remain the
+// simplest way to get just the passphrase.
+type Passphrase struct {
+	text      string
+	words     []string
+	separator string
+	lang      Language
+	entropy   float64
+}
+
+// GeneratePassphrase is like GenerateWithOptions but returns a Passphrase
+// instead of a bare string:
+//
+//	p, err := diceware.GeneratePassphrase(6, diceware.WithSeparator("-"))
+//	fmt.Println(p)            // e.g. Colt-Default-Arousal-...
+//	fmt.Println(p.Entropy())  // 77.5
+//
+// Returns an error if wordCount is less than 1, if the options are invalid,
+// or if random number generation fails.
+func GeneratePassphrase(wordCount int, opts ...Option) (Passphrase, error) {
+	o := newOptions(opts...)
+	text, words, _, err := generate(wordCount, o)
+	if err != nil {
+		return Passphrase{}, err
+	}
+
+	p := Passphrase{
+		text:    text,
+		words:   words,
+		lang:    o.lang,
+		entropy: o.breakdown(wordCount).Total,
+	}
+	// Only a separator the text is really made of: connector words,
+	// grouping and leading or trailing separators change the joins
+	if o.randomSeps == nil && len(o.separators) == 1 && strings.Join(words, o.separators[0]) == text {
+		p.separator = o.separators[0]
+	}
+	if o.wordlists != nil || o.alternate != nil {
+		p.lang = LanguageUnknown
+	}
+	return p, nil
+}
+
+// String returns the passphrase as a single string, exactly as
+// GenerateWithOptions would have returned it.
+func (p Passphrase) String() string {
+	return p.text
+}
+
+// Words returns the words of the passphrase in order, as they appear in it
+// (including a WithNumberWord number). The slice is a fresh copy the
+// caller may modify.
+func (p Passphrase) Words() []string {
+	return slices.Clone(p.words)
+}
+
+// Separator returns the separator the words are joined with, such that
+// String() is strings.Join(Words(), Separator()). It returns "" if there
+// is none, or no such single separator: when it varies (WithSeparators,
+// WithSeparatorRandom, WithConnectorWords) or the passphrase is more than
+// the joined words (WithLeadingSeparator, WithTrailingSeparator,
+// WithGrouping).
+func (p Passphrase) Separator() string {
+	return p.separator
+}
+
+// Language returns the language the words were drawn from, or
+// LanguageUnknown for WithWordlists and WithAlternatingLanguages.
+func (p Passphrase) Language() Language {
+	return p.lang
+}
+
+// Entropy returns the entropy of the passphrase in bits for the options it
+// was generated with, see EntropyWithOptions.
+func (p Passphrase) Entropy() float64 {
+	return p.entropy
+}
+
+// passphraseJSON is the JSON form of a Passphrase.
+type passphraseJSON struct {
+	Passphrase string   `json:"passphrase"`
+	Words      []string `json:"words"`
+	Separator  string   `json:"separator"`
+	Language   string   `json:"language,omitempty"`
+	Entropy    float64  `json:"entropy"`
+}
+
+// MarshalJSON encodes the passphrase as an object with its words,
+// separator, language code (see LanguageInfo.Code; omitted for
+// LanguageUnknown) and entropy, e.g.
+//
+//	{"passphrase":"Colt-Default","words":["Colt","Default"],
+//	 "separator":"-","language":"en","entropy":25.8}
+func (p Passphrase) MarshalJSON() ([]byte, error) {
+	out := passphraseJSON{
+		Passphrase: p.text,
+		Words:      p.words,
+		Separator:  p.separator,
+		Entropy:    p.entropy,
+	}
+	for _, info := range ListLanguages() {
+		if info.Language == p.lang {
+			out.Language = info.Code
+			break
+		}
+	}
+	return json.Marshal(out)
+}
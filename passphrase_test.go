@@ -0,0 +1,76 @@
+package diceware
+
+import (
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGeneratePassphrase(t *testing.T) {
+	p, err := GeneratePassphrase(4, WithLanguage(LanguageRomanian), WithSeparator("-"))
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error = %v", err)
+	}
+	words := p.Words()
+	if len(words) != 4 || p.String() != strings.Join(words, "-") {
+		t.Errorf("String() = %q, Words() = %q, want 4 words joined by -", p.String(), words)
+	}
+	if p.Separator() != "-" || p.Language() != LanguageRomanian {
+		t.Errorf("Separator() = %q, Language() = %v, want - and Romanian", p.Separator(), p.Language())
+	}
+	if want := EntropyForLanguage(4, LanguageRomanian); math.Abs(p.Entropy()-want) > 1e-9 {
+		t.Errorf("Entropy() = %f, want %f", p.Entropy(), want)
+	}
+	words[0] = "changed"
+	if p.Words()[0] == "changed" {
+		t.Error("Words() returned the Passphrase's own slice")
+	}
+
+	data, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got["passphrase"] != p.String() || got["separator"] != "-" || got["language"] != "ro" || got["entropy"] != p.Entropy() {
+		t.Errorf("json.Marshal() = %s", data)
+	}
+	if n := len(got["words"].([]any)); n != 4 {
+		t.Errorf("json.Marshal() has %d words, want 4", n)
+	}
+
+	// No single separator or language to report
+	p, err = GeneratePassphrase(3, WithAlternatingLanguages([]Language{LanguageEnglish, LanguageRomanian}), WithSeparators([]string{"-", "_"}))
+	if err != nil {
+		t.Fatalf("GeneratePassphrase() error = %v", err)
+	}
+	if p.Separator() != "" || p.Language() != LanguageUnknown {
+		t.Errorf("Separator() = %q, Language() = %v, want empty and LanguageUnknown", p.Separator(), p.Language())
+	}
+	if data, _ := json.Marshal(p); strings.Contains(string(data), `"language"`) {
+		t.Errorf("json.Marshal() = %s, want no language", data)
+	}
+
+	// The configured separator isn't the join between the words
+	for _, opts := range [][]Option{
+		{WithSeparator("-"), WithConnectorWords(true)},
+		{WithSeparator("-"), WithLeadingSeparator(true)},
+		{WithSeparator("-"), WithTrailingSeparator(true)},
+		{WithSeparator("-"), WithGrouping(4, " ")},
+	} {
+		p, err := GeneratePassphrase(3, opts...)
+		if err != nil {
+			t.Fatalf("GeneratePassphrase() error = %v", err)
+		}
+		if sep := p.Separator(); sep != "" {
+			t.Errorf("Separator() of %q = %q, want empty", p, sep)
+		}
+	}
+
+	if _, err := GeneratePassphrase(0); err == nil {
+		t.Error("GeneratePassphrase(0) should return an error")
+	}
+}
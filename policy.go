@@ -0,0 +1,141 @@
+package diceware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxPolicyAttempts bounds how many passphrases GenerateForPolicy generates
+// while looking for one that satisfies every rule.
+const maxPolicyAttempts = 100
+
+// defaultPolicySymbols are the separators GenerateForPolicy uses to satisfy
+// RequireSymbol when Policy.Symbols is empty. They are accepted by
+// practically every password form.
+const defaultPolicySymbols = "-_.+!"
+
+// Policy combines the password composition rules of a signup form, for
+// GenerateForPolicy:
+//
+//	passphrase, err := diceware.GenerateForPolicy(diceware.Policy{
+//	    MinLength:     12,
+//	    MaxLength:     64,
+//	    RequireDigit:  true,
+//	    RequireSymbol: true,
+//	    RequireUpper:  true,
+//	}, diceware.LanguageEnglish)
+//
+// The zero value has no rules.
+type Policy struct {
+	// MinLength and MaxLength bound the length in characters; 0 means no
+	// limit.
+	MinLength, MaxLength int
+	// RequireDigit requires at least one digit. It is satisfied by adding
+	// a two-digit number word (see WithNumberWord).
+	RequireDigit bool
+	// RequireSymbol requires at least one character that is neither a
+	// letter, a digit nor a space. It is satisfied by separating the words
+	// with random symbols (see WithSeparatorRandom).
+	RequireSymbol bool
+	// RequireUpper and RequireLower require at least one upper- and
+	// lowercase letter. Words are capitalized, which satisfies both for
+	// any real word.
+	RequireUpper, RequireLower bool
+	// Symbols are the separators to draw from for RequireSymbol, each
+	// character being one choice. The default is "-_.+!".
+	Symbols string
+	// Words is the number of words. 0 picks the word count of
+	// SecurityMedium for the language.
+	Words int
+}
+
+// Check reports the first rule of the policy passphrase breaks, or nil if
+// it satisfies all of them.
+func (p Policy) Check(passphrase string) error {
+	n := utf8.RuneCountInString(passphrase)
+	if n < p.MinLength {
+		return fmt.Errorf("passphrase is %d characters long, shorter than the minimum of %d", n, p.MinLength)
+	}
+	if p.MaxLength > 0 && n > p.MaxLength {
+		return fmt.Errorf("passphrase is %d characters long, longer than the maximum of %d", n, p.MaxLength)
+	}
+
+	var digit, symbol, upper, lower bool
+	for _, r := range passphrase {
+		switch {
+		case unicode.IsDigit(r):
+			digit = true
+		case unicode.IsUpper(r) || unicode.IsTitle(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case !unicode.IsLetter(r) && !unicode.IsSpace(r):
+			symbol = true
+		}
+	}
+	switch {
+	case p.RequireDigit && !digit:
+		return errors.New("passphrase has no digit")
+	case p.RequireSymbol && !symbol:
+		return errors.New("passphrase has no symbol")
+	case p.RequireUpper && !upper:
+		return errors.New("passphrase has no uppercase letter")
+	case p.RequireLower && !lower:
+		return errors.New("passphrase has no lowercase letter")
+	}
+	return nil
+}
+
+// options translates the policy into generation options for lang.
+func (p Policy) options(lang Language) []Option {
+	opts := []Option{
+		WithLanguage(lang),
+		WithMinLength(p.MinLength),
+		WithMaxLength(p.MaxLength),
+	}
+	if p.RequireDigit {
+		opts = append(opts, WithNumberWord(2))
+	}
+	if p.RequireSymbol {
+		symbols := p.Symbols
+		if symbols == "" {
+			symbols = defaultPolicySymbols
+		}
+		opts = append(opts, WithSeparatorRandom(strings.Split(symbols, "")))
+	}
+	return opts
+}
+
+// GenerateForPolicy generates a passphrase in lang that satisfies every rule
+// of policy at once, composing WithMinLength, WithMaxLength, WithNumberWord
+// and WithSeparatorRandom as needed. Passphrases that still break a rule
+// (e.g. a Reinhold word like "@" leaving no lowercase letter) are
+// discarded and generated again, up to a bounded number of attempts.
+//
+// Returns an error if the rules can't be met, e.g. a MaxLength too short
+// for the word count, or if random number generation fails.
+func GenerateForPolicy(policy Policy, lang Language) (string, error) {
+	wordCount := policy.Words
+	if wordCount == 0 {
+		var err error
+		if wordCount, err = WordCountForLevel(SecurityMedium, lang); err != nil {
+			return "", err
+		}
+	}
+
+	opts := policy.options(lang)
+	var lastErr error
+	for attempt := 0; attempt < maxPolicyAttempts; attempt++ {
+		passphrase, err := GenerateWithOptions(wordCount, opts...)
+		if err != nil {
+			return "", err
+		}
+		if lastErr = policy.Check(passphrase); lastErr == nil {
+			return passphrase, nil
+		}
+	}
+	return "", fmt.Errorf("no passphrase satisfying the policy after %d attempts: %w: %w", maxPolicyAttempts, ErrMaxAttempts, lastErr)
+}
@@ -0,0 +1,65 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateForPolicy(t *testing.T) {
+	policy := Policy{
+		MinLength:     12,
+		MaxLength:     64,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		RequireUpper:  true,
+		RequireLower:  true,
+	}
+	for i := 0; i < 20; i++ {
+		passphrase, err := GenerateForPolicy(policy, LanguageEnglish)
+		if err != nil {
+			t.Fatalf("GenerateForPolicy() error = %v", err)
+		}
+		if err := policy.Check(passphrase); err != nil {
+			t.Fatalf("GenerateForPolicy() = %q, which breaks the policy: %v", passphrase, err)
+		}
+	}
+
+	// Custom symbols and word count
+	passphrase, err := GenerateForPolicy(Policy{RequireSymbol: true, Symbols: "#", Words: 3}, LanguageRomanian)
+	if err != nil || strings.Count(passphrase, "#") != 2 {
+		t.Errorf("GenerateForPolicy() = %q, %v, want 3 words separated by #", passphrase, err)
+	}
+
+	// Six words never fit in 10 characters
+	if _, err := GenerateForPolicy(Policy{MaxLength: 10}, LanguageEnglish); err == nil {
+		t.Error("GenerateForPolicy() with an impossible maximum length should return an error")
+	}
+	if _, err := GenerateForPolicy(Policy{}, Language(99)); err == nil {
+		t.Error("GenerateForPolicy() with an unsupported language should return an error")
+	}
+}
+
+func TestPolicyCheck(t *testing.T) {
+	tests := []struct {
+		policy     Policy
+		passphrase string
+		wantErr    bool
+	}{
+		{Policy{}, "", false},
+		{Policy{MinLength: 5}, "Abcd", true},
+		{Policy{MaxLength: 5}, "Abcdef", true},
+		{Policy{MaxLength: 5}, "Ăbcdé", false}, // characters, not bytes
+		{Policy{RequireDigit: true}, "Abc-Def", true},
+		{Policy{RequireDigit: true}, "Abc42Def", false},
+		{Policy{RequireSymbol: true}, "Abc Def", true},
+		{Policy{RequireSymbol: true}, "Abc+Def", false},
+		{Policy{RequireUpper: true}, "abc-def", true},
+		{Policy{RequireLower: true}, "ABC-DEF", true},
+		{Policy{RequireUpper: true, RequireLower: true}, "AbcDef", false},
+	}
+	for _, tt := range tests {
+		if err := tt.policy.Check(tt.passphrase); (err != nil) != tt.wantErr {
+			t.Errorf("%+v.Check(%q) error = %v, wantErr %v", tt.policy, tt.passphrase, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package diceware
+
+import (
+	"crypto/rand"
+	"errors"
+	"io"
+	"os"
+	"time"
+)
+
+// DevRandomPath is the blocking random device on Unix-like systems, for
+// environments whose compliance rules require reading from it directly. Use
+// it with OpenDevRandom and WithRandReader.
+const DevRandomPath = "/dev/random"
+
+// WithRandReader makes generation read its randomness from r instead of
+// crypto/rand.Reader, e.g. to satisfy a policy that names a specific
+// entropy source, or to let auditors confirm which source is used:
+//
+//	f, err := diceware.OpenDevRandom()
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//	passphrase, err := diceware.GenerateWithOptions(6, diceware.WithRandReader(f))
+//
+// By default crypto/rand.Reader is used, which reads from the operating
+// system's CSPRNG: getrandom(2) on Linux (blocking only until the kernel
+// pool is first seeded), arc4random_buf(3) or getentropy(2) on macOS and
+// the BSDs, and ProcessPrng on Windows. On Linux 5.6 and later /dev/random
+// and getrandom(2) return the same stream once seeded, so substituting it
+// changes the audit trail rather than the quality of the randomness.
+//
+// Generation reads r in chunks of a few hundred bytes, so a blocking
+// device like /dev/random is read about once per passphrase rather than
+// once per word.
+//
+// r must be safe for concurrent use if the options are shared between
+// goroutines, e.g. through a Generator. Passing nil restores
+// crypto/rand.Reader. The option only affects the functions that take
+// options; functions like Generate and GenerateTo always use crypto/rand.
+func WithRandReader(r io.Reader) Option {
+	return func(o *options) {
+		if r == nil {
+			r = rand.Reader
+		}
+		o.rand = r
+	}
+}
+
+// defaultRandAttempts is how many times generation reads the random source
+// before giving up with ErrRandomSource, see WithRandAttempts.
+const defaultRandAttempts = 3
+
+// randRetryDelay is the pause before the first retry of a failed read of
+// the random source; it doubles for each further retry.
+const randRetryDelay = time.Millisecond
+
+// WithRandAttempts sets how many times a failed read of the random source
+// is attempted before generation gives up with ErrRandomSource, pausing
+// briefly (1ms, then 2ms, ...) between attempts. A transient failure of
+// the OS source then costs a few milliseconds instead of the passphrase,
+// which matters for long-running services that generate many. The default
+// is 3; 1 disables retrying, and 0 restores the default. Generation
+// returns an error for a negative n.
+//
+// A source that has run out (io.EOF, e.g. a fixed test fixture) is never
+// retried, and no byte is ever read twice, so retrying can't bias or
+// repeat the randomness.
+func WithRandAttempts(n int) Option {
+	return func(o *options) {
+		o.randAttempts = n
+	}
+}
+
+// OpenDevRandom opens DevRandomPath for use with WithRandReader. The caller
+// must close it. It returns an error on systems without the device, e.g.
+// Windows.
+func OpenDevRandom() (io.ReadCloser, error) {
+	return os.Open(DevRandomPath)
+}
+
+// batchBytesPerWord estimates the random bytes one word takes: room for a
+// roll of up to diceCount bytes with the occasional rejected draw (a
+// five-dice roll takes two), and up to 8 bytes for picking a LanguageMixed
+// list, a random separator or a number.
+const batchBytesPerWord = diceCount + 8
+
+// batchReader reads from r in chunks and hands the bytes out in order, so
+// generating a long passphrase costs a few reads of the random source
+// (often one) rather than one per word. The stream itself is unchanged:
+// every byte is used exactly as r produced it.
+//
+// The buffer holds the bytes a passphrase is derived from; call wipe once
+// generation is done. A batchReader is not safe for concurrent use.
+//
+// A failed read of r is retried, up to attempts reads in all, see
+// WithRandAttempts.
+type batchReader struct {
+	r        io.Reader
+	buf      []byte
+	pos, end int
+	attempts int
+}
+
+// newBatchReader returns a batchReader over r that reads enough for
+// wordCount words at once, making the default number of attempts.
+func newBatchReader(r io.Reader, wordCount int) *batchReader {
+	return &batchReader{r: r, buf: make([]byte, wordCount*batchBytesPerWord), attempts: defaultRandAttempts}
+}
+
+func (b *batchReader) Read(p []byte) (int, error) {
+	if err := b.fill(); err != nil {
+		return 0, err
+	}
+	n := copy(p, b.buf[b.pos:b.end])
+	b.pos += n
+	return n, nil
+}
+
+// ReadByte implements io.ByteReader, for rollDice.
+func (b *batchReader) ReadByte() (byte, error) {
+	if err := b.fill(); err != nil {
+		return 0, err
+	}
+	b.pos++
+	return b.buf[b.pos-1], nil
+}
+
+// fill reads the next chunk from r once the buffer is used up, retrying a
+// failed read with a doubling delay.
+func (b *batchReader) fill() error {
+	if b.pos < b.end {
+		return nil
+	}
+	delay := randRetryDelay
+	for attempt := 1; ; attempt++ {
+		n, err := b.r.Read(b.buf)
+		if n > 0 {
+			b.pos, b.end = 0, n
+			return nil
+		}
+		if err == nil {
+			err = io.ErrNoProgress
+		}
+		if attempt >= b.attempts || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// wipe zeroes the buffer, see Wipe.
+func (b *batchReader) wipe() {
+	Wipe(b.buf)
+	b.pos, b.end = 0, 0
+}
@@ -0,0 +1,234 @@
+package diceware
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	mrand "math/rand"
+	"os"
+	"sync/atomic"
+	"testing"
+)
+
+// countingReader counts the bytes and reads going through it, to show which
+// source generation actually uses and how often.
+type countingReader struct {
+	r     io.Reader
+	n     atomic.Int64
+	reads atomic.Int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n.Add(int64(n))
+	c.reads.Add(1)
+	return n, err
+}
+
+func TestWithRandReader(t *testing.T) {
+	src := &countingReader{r: rand.Reader}
+	if _, err := GenerateWithOptions(6, WithRandReader(src)); err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	if src.n.Load() == 0 {
+		t.Error("generation didn't read from the substituted reader")
+	}
+
+	// A Generator uses it too, and a seeded one overrides it
+	src.n.Store(0)
+	if _, err := NewGenerator(WithRandReader(src)).Generate(4); err != nil || src.n.Load() == 0 {
+		t.Errorf("Generator didn't read from the substituted reader (err = %v)", err)
+	}
+	src.n.Store(0)
+	if _, err := NewSeededGenerator(1, WithRandReader(src)).Generate(4); err != nil || src.n.Load() != 0 {
+		t.Errorf("seeded Generator read from the substituted reader (err = %v)", err)
+	}
+
+	// A deterministic source yields a deterministic passphrase
+	fixed := func() io.Reader { return &seededReader{rng: mrand.New(mrand.NewSource(42))} }
+	a, _ := GenerateWithOptions(6, WithRandReader(fixed()))
+	b, _ := GenerateWithOptions(6, WithRandReader(fixed()))
+	if a != b {
+		t.Errorf("same source gave %q and %q", a, b)
+	}
+
+	if _, err := GenerateWithOptions(6, WithRandReader(failingReader{})); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("GenerateWithOptions() with a failing reader error = %v, want ErrRandomSource", err)
+	}
+	if _, err := GenerateWithOptions(6, WithRandReader(failingReader{}), WithRandReader(nil)); err != nil {
+		t.Errorf("WithRandReader(nil) should restore crypto/rand, got error %v", err)
+	}
+}
+
+// flakyReader fails its first failures reads, then reads from crypto/rand.
+type flakyReader struct {
+	failures int
+	reads    int
+}
+
+func (f *flakyReader) Read(p []byte) (int, error) {
+	f.reads++
+	if f.reads <= f.failures {
+		return 0, errReadFailed
+	}
+	return rand.Read(p)
+}
+
+func TestWithRandAttempts(t *testing.T) {
+	tests := []struct {
+		opts     []Option
+		failures int
+		ok       bool
+	}{
+		{nil, 2, true},
+		{nil, 3, false},
+		{[]Option{WithRandAttempts(1)}, 1, false},
+		{[]Option{WithRandAttempts(5)}, 4, true},
+		{[]Option{WithRandAttempts(5), WithRandAttempts(0)}, 3, false},
+	}
+	for _, tt := range tests {
+		src := &flakyReader{failures: tt.failures}
+		_, err := GenerateWithOptions(6, append(tt.opts, WithRandReader(src))...)
+		if ok := err == nil; ok != tt.ok {
+			t.Errorf("%d failures with %d options: error = %v, want success %v", tt.failures, len(tt.opts), err, tt.ok)
+		}
+		if err != nil && !errors.Is(err, ErrRandomSource) {
+			t.Errorf("error = %v, want ErrRandomSource", err)
+		}
+	}
+
+	// An exhausted source isn't retried
+	src := &countingReader{r: bytes.NewReader(nil)}
+	if _, err := GenerateWithOptions(6, WithRandReader(src)); !errors.Is(err, ErrRandomSource) || src.reads.Load() != 1 {
+		t.Errorf("exhausted source: error = %v after %d reads, want ErrRandomSource after 1", err, src.reads.Load())
+	}
+
+	if _, err := GenerateWithOptions(6, WithRandAttempts(-1)); err == nil {
+		t.Error("WithRandAttempts(-1) should return an error")
+	}
+}
+
+func TestOpenDevRandom(t *testing.T) {
+	if _, err := os.Stat(DevRandomPath); err != nil {
+		t.Skipf("%s not available: %v", DevRandomPath, err)
+	}
+	f, err := OpenDevRandom()
+	if err != nil {
+		t.Fatalf("OpenDevRandom() error = %v", err)
+	}
+	defer f.Close()
+
+	if _, err := GenerateWithOptions(6, WithRandReader(f)); err != nil {
+		t.Errorf("GenerateWithOptions() from %s error = %v", DevRandomPath, err)
+	}
+}
+
+func TestBatchReader(t *testing.T) {
+	// A 20-word passphrase needs a single read of the source
+	src := &countingReader{r: rand.Reader}
+	if _, err := GenerateWithOptions(20, WithRandReader(src), WithSeparator("-")); err != nil {
+		t.Fatalf("GenerateWithOptions() error = %v", err)
+	}
+	if got := src.reads.Load(); got != 1 {
+		t.Errorf("generating 20 words read the source %d times, want 1", got)
+	}
+
+	// The bytes come out as the source produced them, across refills
+	want := make([]byte, 1000)
+	mrand.New(mrand.NewSource(7)).Read(want)
+	br := newBatchReader(mrand.New(mrand.NewSource(7)), 3)
+	got := make([]byte, 0, len(want))
+	for len(got) < len(want) {
+		if len(got)%2 == 0 {
+			b, err := br.ReadByte()
+			if err != nil {
+				t.Fatalf("ReadByte() error = %v", err)
+			}
+			got = append(got, b)
+			continue
+		}
+		p := make([]byte, min(17, len(want)-len(got)))
+		if _, err := io.ReadFull(br, p); err != nil {
+			t.Fatalf("Read() error = %v", err)
+		}
+		got = append(got, p...)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("batchReader changed the byte stream")
+	}
+
+	br.wipe()
+	for _, b := range br.buf {
+		if b != 0 {
+			t.Fatal("wipe() left bytes in the buffer")
+		}
+	}
+}
+
+// TestRollDiceUniform feeds every byte value to rollDice once: each face
+// must come up exactly 42 times, the 4 biased values being rejected.
+func TestRollDiceUniform(t *testing.T) {
+	all := make([]byte, 256)
+	for i := range all {
+		all[i] = byte(i)
+	}
+	r := bytes.NewReader(all)
+
+	counts := make(map[int]int)
+	for i := 0; i < dieRejectAbove; i++ {
+		face, err := rollDice(r)
+		if err != nil {
+			t.Fatalf("rollDice() error = %v", err)
+		}
+		counts[face]++
+	}
+	for face := 1; face <= 6; face++ {
+		if counts[face] != dieRejectAbove/6 {
+			t.Errorf("face %d came up %d times, want %d", face, counts[face], dieRejectAbove/6)
+		}
+	}
+	if _, err := rollDice(r); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("rollDice() on only rejected bytes error = %v, want ErrRandomSource", err)
+	}
+}
+
+// TestRollUniform feeds rollUniform every two-byte value once: each of the
+// 7,776 rolls must come up exactly 8 times, the values from 62,208 up
+// being rejected.
+func TestRollUniform(t *testing.T) {
+	all := make([]byte, 2<<16)
+	for i := 0; i < 1<<16; i++ {
+		all[2*i], all[2*i+1] = byte(i>>8), byte(i)
+	}
+	r := bytes.NewReader(all)
+
+	counts := make([]int, rollCombinations)
+	for i := 0; i < 62208; i++ {
+		idx, err := rollUniform(r, rollCombinations)
+		if err != nil {
+			t.Fatalf("rollUniform() error = %v", err)
+		}
+		counts[idx]++
+	}
+	for idx, n := range counts {
+		if n != 8 {
+			t.Fatalf("roll %s came up %d times, want 8", indexToRoll(idx, diceCount), n)
+		}
+	}
+	if _, err := rollUniform(r, rollCombinations); !errors.Is(err, ErrRandomSource) {
+		t.Errorf("rollUniform() on only rejected values error = %v, want ErrRandomSource", err)
+	}
+
+	// A single byte covers the 6 faces of one die, three the 6^7 rolls of
+	// seven dice
+	for _, tt := range []struct{ n, bytes int }{{6, 1}, {rollCount(7), 3}} {
+		c := &countingReader{r: rand.Reader}
+		if _, err := rollUniform(c, tt.n); err != nil {
+			t.Fatalf("rollUniform(%d) error = %v", tt.n, err)
+		}
+		if got := c.n.Load(); got%int64(tt.bytes) != 0 {
+			t.Errorf("rollUniform(%d) read %d bytes, want a multiple of %d", tt.n, got, tt.bytes)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package diceware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+var (
+	// wordlistsMu guards wordlists and nextLanguage.
+	wordlistsMu sync.RWMutex
+
+	// wordlists is the language registry: the parsed wordlist behind every
+	// Language added by RegisterLanguage. The built-in lists aren't in it;
+	// builtinWordlist parses them on first use.
+	wordlists = make(map[Language]*Wordlist)
+
+	// nextLanguage is the value RegisterLanguage hands out next. It starts
+	// after the built-in constants so they keep their meaning.
+	nextLanguage = numBuiltinLanguages
+)
+
+// lookupWordlist returns the wordlist for lang, built-in or registered.
+func lookupWordlist(lang Language) (*Wordlist, bool) {
+	if lang >= 0 && lang < numBuiltinLanguages {
+		return builtinWordlist(lang)
+	}
+	wordlistsMu.RLock()
+	defer wordlistsMu.RUnlock()
+	wl, ok := wordlists[lang]
+	return wl, ok
+}
+
+// RegisterLanguage reads a wordlist in the standard "<roll> <word>" line
+// format from r and registers it under a new Language value, which can then
+// be used with GenerateWithLanguage, WithLanguage, WordlistByLanguage and
+// the other language-based functions just like the built-in constants:
+//
+//	f, err := os.Open("de_diceware.txt")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	defer f.Close()
+//	german, err := diceware.RegisterLanguage("German", f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	passphrase, err := diceware.GenerateWithLanguage(6, german)
+//
+// The list doesn't have to cover all 7,776 rolls (see NewWordlist). Names
+// are matched case-insensitively and must be unique, built-in names,
+// codes and aliases ("en", "mix", "original", ...) included. Returns an
+// error if the name is empty or taken, or if the wordlist can't be read or
+// parsed.
+func RegisterLanguage(name string, r io.Reader) (Language, error) {
+	if strings.TrimSpace(name) == "" {
+		return 0, errors.New("language name must not be empty")
+	}
+
+	wl, err := LoadWordlist(name, r)
+	if err != nil {
+		return 0, err
+	}
+
+	wordlistsMu.Lock()
+	defer wordlistsMu.Unlock()
+
+	for _, b := range builtinWordlists {
+		if strings.EqualFold(b.name, name) {
+			return 0, fmt.Errorf("language %q is already registered", name)
+		}
+	}
+	for _, b := range builtinLanguages {
+		if strings.EqualFold(b.info.Code, name) {
+			return 0, fmt.Errorf("language %q is already registered", name)
+		}
+		for _, alias := range b.aliases {
+			if strings.EqualFold(alias, name) {
+				return 0, fmt.Errorf("language %q is already registered", name)
+			}
+		}
+	}
+	for _, existing := range wordlists {
+		if strings.EqualFold(existing.name, name) {
+			return 0, fmt.Errorf("language %q is already registered", name)
+		}
+	}
+
+	lang := nextLanguage
+	nextLanguage++
+	wl.lang = lang
+	wordlists[lang] = wl
+	return lang, nil
+}
+
+// LoadWordlist reads a wordlist in the standard "<roll> <word>" line format
+// from r, e.g. a file in the same format as the EFF large wordlist, without
+// registering it. Use it with WithWordlists or GenerateFromWordlists.
+//
+// Lines starting with '#' are comments, and everything after the roll is
+// the word, so multi-word entries are allowed. Returns an error naming the
+// offending line if a line is malformed (see LoadWordlistLenient to skip
+// such lines instead), or an error wrapping ErrEmptyWordlist if there are no
+// entries, e.g. for an empty or whitespace-only file.
+func LoadWordlist(name string, r io.Reader) (*Wordlist, error) {
+	return loadWordlist(name, r, true)
+}
+
+// LoadWordlistLenient is like LoadWordlist but skips malformed lines (a
+// missing word, an invalid roll, or a roll already seen) instead of failing,
+// for community lists with stray junk. The skipped lines aren't lost:
+// Wordlist.Skipped reports each one. It still returns an error wrapping
+// ErrEmptyWordlist if no valid entries remain.
+func LoadWordlistLenient(name string, r io.Reader) (*Wordlist, error) {
+	return loadWordlist(name, r, false)
+}
+
+// loadWordlist is the shared implementation of LoadWordlist and
+// LoadWordlistLenient.
+func loadWordlist(name string, r io.Reader, strict bool) (*Wordlist, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read wordlist %q: %w", name, err)
+	}
+
+	words, n, skipped, err := readWordlist(string(data), strict)
+	if err != nil {
+		return nil, fmt.Errorf("wordlist %q: %w", name, err)
+	}
+	if n == 0 {
+		if len(skipped) > 0 {
+			return nil, fmt.Errorf("%w: %q has no entries, all %d of its lines are malformed (first: %v)", ErrEmptyWordlist, name, len(skipped), skipped[0])
+		}
+		return nil, fmt.Errorf("%w: %q has no entries, only blank lines or comments", ErrEmptyWordlist, name)
+	}
+
+	wl := newWordlist(name, words, nil)
+	wl.skipped = skipped
+	return wl, nil
+}
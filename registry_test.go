@@ -0,0 +1,156 @@
+package diceware
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// errReadFailed is the error failingReader returns.
+var errReadFailed = errors.New("read failed")
+
+// failingReader is an io.Reader that always fails.
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errReadFailed
+}
+
+func TestLoadWordlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		wantSize int
+		wantErr  string
+	}{
+		{"valid", "11111 alpha\n11112 beta\n\n11113 gamma\n", 3, ""},
+		{"empty", "\n\n", 0, "no entries"},
+		{"empty file", "", 0, "no entries"},
+		{"whitespace only", "\ufeff \t\r\n  \n", 0, "no entries"},
+		{"comments only", "# nothing yet\n", 0, "no entries"},
+		{"comments and multi-word entries", "# My list\n11111 alpha\n11112 ice   cream\n", 2, ""},
+		{"missing word", "11111 alpha\n11112\n", 0, "line 2"},
+		{"invalid roll", "11111 alpha\n11117 beta\n", 0, "line 2"},
+		{"duplicate roll", "11111 alpha\n\n11111 beta\n", 0, "line 3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wl, err := LoadWordlist("test", strings.NewReader(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadWordlist() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadWordlist() error = %v", err)
+			}
+			if wl.Size() != tt.wantSize {
+				t.Errorf("Size() = %d, want %d", wl.Size(), tt.wantSize)
+			}
+		})
+	}
+
+	if _, err := LoadWordlist("test", failingReader{}); err == nil {
+		t.Error("LoadWordlist() should return an error when the reader fails")
+	}
+	for _, data := range []string{"", "  \n\t\n"} {
+		if _, err := LoadWordlist("test", strings.NewReader(data)); !errors.Is(err, ErrEmptyWordlist) {
+			t.Errorf("LoadWordlist(%q) error = %v, want ErrEmptyWordlist", data, err)
+		}
+	}
+	if _, err := LoadWordlistLenient("test", strings.NewReader("junk\n11117 bad\n")); !errors.Is(err, ErrEmptyWordlist) || !strings.Contains(err.Error(), "malformed") {
+		t.Errorf("LoadWordlistLenient() error = %v, want ErrEmptyWordlist for malformed lines", err)
+	}
+	if _, err := NewWordlist("test", nil); !errors.Is(err, ErrEmptyWordlist) {
+		t.Errorf("NewWordlist(nil) error = %v, want ErrEmptyWordlist", err)
+	}
+}
+
+func TestLoadWordlistMultiWord(t *testing.T) {
+	wl, err := LoadWordlist("test", strings.NewReader("# comment\n11111 ice   cream\n"))
+	if err != nil {
+		t.Fatalf("LoadWordlist() error = %v", err)
+	}
+	if got := wl.words[0]; got != "ice cream" {
+		t.Errorf("entry = %q, want %q", got, "ice cream")
+	}
+	if got, _ := GenerateWithOptions(1, WithWordlists(wl)); got != "Ice cream" {
+		t.Errorf("GenerateWithOptions() = %q, want %q", got, "Ice cream")
+	}
+}
+
+func TestLoadWordlistLenient(t *testing.T) {
+	data := "11111 alpha\n11112\n11117 bad\n11111 duplicate\n11113 gamma\n"
+	if _, err := LoadWordlist("test", strings.NewReader(data)); err == nil {
+		t.Fatal("LoadWordlist() should reject the malformed lines")
+	}
+
+	wl, err := LoadWordlistLenient("test", strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadWordlistLenient() error = %v", err)
+	}
+	if wl.Size() != 2 || wl.words[0] != "alpha" || wl.words[2] != "gamma" {
+		t.Errorf("LoadWordlistLenient() entries = %q, want alpha and gamma", wl.words[:3])
+	}
+	skipped := wl.Skipped()
+	if len(skipped) != 3 {
+		t.Fatalf("Skipped() = %v, want 3 errors", skipped)
+	}
+	for i, want := range []string{"line 2", "line 3", "line 4"} {
+		if !strings.Contains(skipped[i].Error(), want) {
+			t.Errorf("Skipped()[%d] = %v, want it to name %s", i, skipped[i], want)
+		}
+	}
+	if !strings.Contains(skipped[0].Error(), "expected a dice roll and a word") {
+		t.Errorf("Skipped()[0] = %v, want the missing word reported", skipped[0])
+	}
+	if strict, _ := LoadWordlist("test", strings.NewReader("11111 alpha\n")); strict.Skipped() != nil {
+		t.Errorf("Skipped() = %v for a strictly loaded list, want nil", strict.Skipped())
+	}
+
+	if _, err := LoadWordlistLenient("test", strings.NewReader("junk\n# only junk\n")); err == nil {
+		t.Error("LoadWordlistLenient() with no valid entries should return an error")
+	}
+}
+
+func TestRegisterLanguage(t *testing.T) {
+	lang, err := RegisterLanguage("Registry Test", strings.NewReader("11111 alpha\n11112 beta\n"))
+	if err != nil {
+		t.Fatalf("RegisterLanguage() error = %v", err)
+	}
+	if lang == LanguageEnglish || lang == LanguageRomanian || lang == LanguageMixed {
+		t.Fatalf("RegisterLanguage() returned built-in language %v", lang)
+	}
+
+	if got := WordlistSizeByLanguage(lang); got != 2 {
+		t.Errorf("WordlistSizeByLanguage() = %d, want 2", got)
+	}
+	wl, err := WordlistByLanguage(lang)
+	if err != nil || wl.Name() != "Registry Test" {
+		t.Errorf("WordlistByLanguage() = %v, %v, want the registered list", wl, err)
+	}
+
+	passphrase, err := GenerateWithLanguage(8, lang)
+	if err != nil {
+		t.Fatalf("GenerateWithLanguage() error = %v", err)
+	}
+	if strings.NewReplacer("Alpha", "", "Beta", "").Replace(passphrase) != "" {
+		t.Errorf("GenerateWithLanguage() = %q, want only registered words", passphrase)
+	}
+
+	for _, name := range []string{"registry test", "english", "ROMANIAN", "en", "RO", "mix", "original", "bip39-en", " "} {
+		if _, err := RegisterLanguage(name, strings.NewReader("11111 alpha\n")); err == nil {
+			t.Errorf("RegisterLanguage(%q) should return an error", name)
+		}
+	}
+	if _, err := RegisterLanguage("Broken", strings.NewReader("11111\n")); err == nil {
+		t.Error("RegisterLanguage() with a malformed wordlist should return an error")
+	}
+
+	// The built-in constants are unaffected
+	if WordlistSizeByLanguage(LanguageEnglish) != 7776 {
+		t.Errorf("English size changed after registration: %d", WordlistSizeByLanguage(LanguageEnglish))
+	}
+}
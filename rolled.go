@@ -0,0 +1,42 @@
+package diceware
+
+// RolledWord records how one word of a passphrase was chosen. In
+// LanguageMixed (or with several WithWordlists lists) the same roll maps to
+// a different word in each list, so the roll alone doesn't say which word
+// it produced; Lang and Wordlist do.
+type RolledWord struct {
+	// Word is the word as it appears in the passphrase.
+	Word string
+	// Roll is the dice roll that selected the word, e.g. "43434", one
+	// digit per die of its wordlist, or its index, e.g. "1024", for
+	// LanguageBIP39English.
+	// It is empty for a WithNumberWord number, which isn't rolled.
+	Roll string
+	// Lang is the language of the wordlist the word came from, or
+	// LanguageUnknown for an unregistered WithWordlists list or a
+	// WithNumberWord number.
+	Lang Language
+	// Wordlist is the name of the wordlist the word came from, e.g.
+	// "Romanian". It is empty for a WithNumberWord number.
+	Wordlist string
+}
+
+// GenerateWithRolledWords is like GenerateWithRollsAndOptions but reports,
+// for every word, the wordlist it was rolled against along with the roll,
+// so a mixed-language passphrase can be reconstructed exactly:
+//
+//	passphrase, words, err := diceware.GenerateWithRolledWords(6,
+//	    diceware.WithLanguage(diceware.LanguageMixed))
+//	for _, w := range words {
+//	    fmt.Println(w.Roll, w.Wordlist, w.Word) // e.g. 11115 Romanian Abajur
+//	}
+//
+// Returns an error if wordCount is less than 1, if the options are invalid,
+// or if random number generation fails.
+func GenerateWithRolledWords(wordCount int, opts ...Option) (passphrase string, words []RolledWord, err error) {
+	passphrase, _, words, err = generate(wordCount, newOptions(opts...))
+	if err != nil {
+		return "", nil, err
+	}
+	return passphrase, words, nil
+}
@@ -0,0 +1,65 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateWithRolledWords(t *testing.T) {
+	passphrase, words, err := GenerateWithRolledWords(20, WithLanguage(LanguageMixed), WithSeparator(" "))
+	if err != nil {
+		t.Fatalf("GenerateWithRolledWords() error = %v", err)
+	}
+	if len(words) != 20 {
+		t.Fatalf("GenerateWithRolledWords() returned %d words, want 20", len(words))
+	}
+
+	shown := strings.Split(passphrase, " ")
+	for i, w := range words {
+		if w.Word != shown[i] {
+			t.Errorf("word %d = %q, passphrase has %q", i, w.Word, shown[i])
+		}
+		if w.Lang != LanguageEnglish && w.Lang != LanguageRomanian {
+			t.Errorf("word %q has language %v, want English or Romanian", w.Word, w.Lang)
+			continue
+		}
+		// The roll and language together identify the word
+		if got, err := WordAt(w.Roll, w.Lang); err != nil || got != w.Word {
+			t.Errorf("WordAt(%q, %v) = %q, %v, want %q", w.Roll, w.Lang, got, err, w.Word)
+		}
+		if wl, _ := WordlistByLanguage(w.Lang); wl.Name() != w.Wordlist {
+			t.Errorf("word %q: Wordlist = %q, want %q", w.Word, w.Wordlist, wl.Name())
+		}
+	}
+}
+
+func TestRolledWordSources(t *testing.T) {
+	custom := testWordlist(t, "custom", 10)
+	_, words, err := GenerateWithRolledWords(2, WithWordlists(custom), WithNumberWord(2))
+	if err != nil {
+		t.Fatalf("GenerateWithRolledWords() error = %v", err)
+	}
+	numbers := 0
+	for _, w := range words {
+		switch {
+		case w.Roll == "":
+			numbers++
+			if w.Lang != LanguageUnknown || w.Wordlist != "" {
+				t.Errorf("number word %+v should have no language or wordlist", w)
+			}
+		case w.Lang != LanguageUnknown || w.Wordlist != "custom":
+			t.Errorf("word %+v should come from the unregistered custom list", w)
+		}
+	}
+	if numbers != 1 {
+		t.Errorf("got %d number words, want 1", numbers)
+	}
+
+	// Blocklists derive new lists, which keep their language
+	_, words, _ = GenerateWithRolledWords(3, WithLanguage(LanguageRomanian), WithBlocklist([]string{"abajur"}))
+	for _, w := range words {
+		if w.Lang != LanguageRomanian {
+			t.Errorf("word %+v should be tagged Romanian", w)
+		}
+	}
+}
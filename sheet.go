@@ -0,0 +1,48 @@
+package diceware
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// rerollMark is printed by PrintWordlist for rolls that have no usable
+// word, telling someone rolling physical dice to roll again.
+const rerollMark = "(roll again)"
+
+// PrintWordlist writes the complete roll-to-word table of the specified
+// language's wordlist to w, one "<roll>\t<word>" line per roll in order
+// (11111 to 66666 for the five-dice lists), so it can be printed and used
+// with physical dice offline. The output is in the same format the
+// wordlist loaders read.
+//
+// Rolls without a usable word (e.g. Romanian filler entries, or rolls a
+// partial registered list doesn't cover) are listed with "(roll again)",
+// matching the rerolls generation does. LanguageMixed has no single
+// wordlist and returns an error; print each list separately instead.
+// LanguageBIP39English isn't rolled with dice and returns an error too.
+func PrintWordlist(w io.Writer, lang Language) error {
+	if lang == LanguageMixed {
+		return errors.New("LanguageMixed combines several wordlists; print each one separately")
+	}
+	wl, err := WordlistByLanguage(lang)
+	if err != nil {
+		return err
+	}
+	if wl.bits > 0 {
+		return fmt.Errorf("the %s wordlist is indexed by %d random bits, not dice rolls", wl.name, wl.bits)
+	}
+
+	bw := bufio.NewWriter(w)
+	for i, word := range wl.words {
+		if word == "" || !wl.accepts(word) {
+			word = rerollMark
+		}
+		bw.WriteString(indexToRoll(i, wl.dice))
+		bw.WriteByte('\t')
+		bw.WriteString(word)
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
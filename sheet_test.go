@@ -0,0 +1,58 @@
+package diceware
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPrintWordlist(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintWordlist(&buf, LanguageRomanian); err != nil {
+		t.Fatalf("PrintWordlist() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != rollCombinations {
+		t.Fatalf("PrintWordlist() wrote %d lines, want %d", len(lines), rollCombinations)
+	}
+	if lines[0] != "11111\taaa" || lines[len(lines)-1][:6] != "66666\t" {
+		t.Errorf("first/last lines = %q, %q, want rolls 11111 and 66666", lines[0], lines[len(lines)-1])
+	}
+
+	rerolls := 0
+	for i, line := range lines {
+		roll, word, _ := strings.Cut(line, "\t")
+		if roll != indexToRoll(i, diceCount) {
+			t.Fatalf("line %d has roll %q, want %q", i+1, roll, indexToRoll(i, diceCount))
+		}
+		if word == rerollMark {
+			rerolls++
+		}
+	}
+	if want := rollCombinations - WordlistSizeByLanguage(LanguageRomanian); rerolls != want {
+		t.Errorf("got %d reroll lines, want %d", rerolls, want)
+	}
+
+	// The sheet reads back as the same list
+	en, _ := WordlistByLanguage(LanguageEnglish)
+	buf.Reset()
+	if err := PrintWordlist(&buf, LanguageEnglish); err != nil {
+		t.Fatalf("PrintWordlist() error = %v", err)
+	}
+	back, err := LoadWordlist("sheet", &buf)
+	if err != nil {
+		t.Fatalf("LoadWordlist() on the sheet error = %v", err)
+	}
+	for i := range en.words {
+		if back.words[i] != en.words[i] {
+			t.Fatalf("roll %s reads back as %q, want %q", indexToRoll(i, diceCount), back.words[i], en.words[i])
+		}
+	}
+
+	for _, lang := range []Language{LanguageMixed, Language(99)} {
+		if err := PrintWordlist(&buf, lang); err == nil {
+			t.Errorf("PrintWordlist(%v) should return an error", lang)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package diceware
+
+import "fmt"
+
+// WordlistSource supplies a wordlist's roll-to-word mapping, wherever it
+// comes from: an embedded file, a download, a database. Generation takes
+// sources through WithWordlistSources, so callers can treat all of them
+// alike. The built-in languages are sources (see Language.Wordlist), and
+// so is any *Wordlist; other implementations typically build their list
+// with NewWordlist or LoadWordlist:
+//
+//	src := diceware.WordlistSourceFunc(func() (*diceware.Wordlist, error) {
+//	    resp, err := http.Get(url)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    defer resp.Body.Close()
+//	    return diceware.LoadWordlist("remote", resp.Body)
+//	})
+type WordlistSource interface {
+	// Wordlist returns the list, loading it if needed.
+	Wordlist() (*Wordlist, error)
+}
+
+// WordlistSourceFunc adapts a function to a WordlistSource.
+type WordlistSourceFunc func() (*Wordlist, error)
+
+// Wordlist calls f.
+func (f WordlistSourceFunc) Wordlist() (*Wordlist, error) {
+	return f()
+}
+
+// Wordlist returns the language's wordlist, making every built-in or
+// registered Language a WordlistSource. It returns an error for
+// LanguageMixed, which has no single list, and unsupported languages, like
+// WordlistByLanguage.
+func (lang Language) Wordlist() (*Wordlist, error) {
+	return WordlistByLanguage(lang)
+}
+
+// Wordlist returns wl itself, so a loaded list can be passed wherever a
+// WordlistSource is expected.
+func (wl *Wordlist) Wordlist() (*Wordlist, error) {
+	return wl, nil
+}
+
+// WithWordlistSources draws words from the lists of the given sources,
+// like WithWordlists. Each source is loaded once, when WithWordlistSources
+// is called, so a Generator reusing the option doesn't fetch its lists
+// again for every passphrase. If a source fails, generation returns its
+// error.
+func WithWordlistSources(srcs ...WordlistSource) Option {
+	lists := make([]*Wordlist, len(srcs))
+	var loadErr error
+	for i, src := range srcs {
+		wl, err := src.Wordlist()
+		if err != nil {
+			loadErr = fmt.Errorf("failed to load wordlist source %d: %w", i+1, err)
+			break
+		}
+		lists[i] = wl
+	}
+	return func(o *options) {
+		WithWordlists(lists...)(o)
+		o.sourceErr = loadErr
+	}
+}
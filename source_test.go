@@ -0,0 +1,51 @@
+package diceware
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestWithWordlistSources(t *testing.T) {
+	custom, err := NewWordlist("custom", map[string]string{"1": "alpha", "2": "beta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loads := 0
+	remote := WordlistSourceFunc(func() (*Wordlist, error) {
+		loads++
+		return custom, nil
+	})
+
+	gen := NewGenerator(WithWordlistSources(remote), WithSeparator(" "))
+	for i := 0; i < 3; i++ {
+		passphrase, err := gen.Generate(4)
+		if err != nil {
+			t.Fatalf("Generate() error = %v", err)
+		}
+		for _, word := range strings.Split(passphrase, " ") {
+			if word != "Alpha" && word != "Beta" {
+				t.Errorf("word %q isn't from the source's list", word)
+			}
+		}
+	}
+	if loads != 1 {
+		t.Errorf("source loaded %d times, want once", loads)
+	}
+
+	// Languages and lists are sources too
+	if got, want := EntropyWithOptions(2, WithWordlistSources(LanguageEnglish, custom)), EntropyWithOptions(2, WithWordlists(builtin(LanguageEnglish), custom)); got != want {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, want)
+	}
+	if _, err := GenerateWithOptions(4, WithWordlistSources(LanguageMixed)); err == nil {
+		t.Error("LanguageMixed has no single list and should return an error as a source")
+	}
+
+	failing := WordlistSourceFunc(func() (*Wordlist, error) { return nil, errReadFailed })
+	if _, err := GenerateWithOptions(4, WithWordlistSources(custom, failing)); !errors.Is(err, errReadFailed) {
+		t.Errorf("failing source error = %v, want its error", err)
+	}
+	if _, err := GenerateWithOptions(4, WithWordlistSources(failing), WithWordlists(custom)); err != nil {
+		t.Errorf("a later WithWordlists should replace a failed source, got error %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package diceware
+
+import "context"
+
+// GenerateStream generates passphrases of wordCount words in lang on a
+// background goroutine and sends them on the returned channel until ctx is
+// cancelled, for consumers that need an unbounded supply (e.g. load tests).
+// The channel is unbuffered, so generation only runs as fast as the
+// consumer reads.
+//
+// If generation fails, e.g. because wordCount is invalid, the error is sent
+// as the last Result. The channel is closed when the stream ends either
+// way; cancel ctx when done reading so the goroutine can exit.
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	for res := range diceware.GenerateStream(ctx, 6, diceware.LanguageEnglish) {
+//	    if res.Err != nil {
+//	        log.Fatal(res.Err)
+//	    }
+//	    use(res.Passphrase)
+//	}
+func GenerateStream(ctx context.Context, wordCount int, lang Language) <-chan Result {
+	ch := make(chan Result)
+	go func() {
+		defer close(ch)
+		for {
+			res, err := GenerateDetailed(wordCount, WithLanguage(lang))
+			res.Err = err
+			select {
+			case ch <- res:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return ch
+}
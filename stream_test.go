@@ -0,0 +1,46 @@
+package diceware
+
+import (
+	"context"
+	"testing"
+)
+
+func TestGenerateStream(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := GenerateStream(ctx, 4, LanguageMixed)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 50; i++ {
+		res, ok := <-ch
+		if !ok {
+			t.Fatal("stream closed early")
+		}
+		if res.Err != nil {
+			t.Fatalf("stream error = %v", res.Err)
+		}
+		seen[res.Passphrase] = true
+	}
+	if len(seen) < 45 {
+		t.Errorf("got only %d distinct passphrases out of 50", len(seen))
+	}
+
+	cancel()
+	// After cancellation the goroutine exits and closes the channel; at
+	// most one value already in flight may still arrive.
+	for range ch {
+	}
+}
+
+func TestGenerateStreamError(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch := GenerateStream(ctx, 0, LanguageEnglish)
+	res, ok := <-ch
+	if !ok || res.Err == nil {
+		t.Fatalf("first result = %+v, %v, want an error", res, ok)
+	}
+	if _, ok := <-ch; ok {
+		t.Error("stream should be closed after an error")
+	}
+}
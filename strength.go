@@ -0,0 +1,88 @@
+package diceware
+
+import (
+	"math"
+	"time"
+)
+
+// Attacker guess rates for use with EstimateCrackTime, in guesses per second.
+// They are rough, commonly quoted orders of magnitude rather than
+// measurements of any particular system.
+const (
+	// GuessRateOnlineThrottled models an online attack against a service
+	// that rate limits login attempts: about 100 guesses per hour.
+	GuessRateOnlineThrottled = 100.0 / 3600
+
+	// GuessRateOfflineGPU models an offline attack on a leaked fast hash
+	// (e.g. unsalted SHA-256) with a rig of consumer GPUs: 10^10 guesses
+	// per second.
+	GuessRateOfflineGPU = 1e10
+
+	// GuessRateOfflineASIC models a well-funded attacker with custom
+	// hardware against a fast hash: 10^12 guesses per second.
+	GuessRateOfflineASIC = 1e12
+)
+
+// EstimateCrackTime converts entropy into the average time an attacker
+// making guessesPerSecond guesses would need to find the passphrase. On
+// average the search succeeds halfway through the keyspace, i.e. after
+// 2^(entropyBits-1) guesses:
+//
+//	bits := diceware.EntropyForLanguage(6, diceware.LanguageEnglish)
+//	d := diceware.EstimateCrackTime(bits, diceware.GuessRateOfflineGPU)
+//
+// time.Duration can't represent more than about 292 years, so longer
+// estimates (which is most of them for 6+ words) saturate at the maximum
+// Duration; treat that value as "longer than 292 years". A guessesPerSecond
+// of zero or less also returns the maximum.
+func EstimateCrackTime(entropyBits float64, guessesPerSecond float64) time.Duration {
+	const maxDuration = time.Duration(math.MaxInt64)
+
+	if guessesPerSecond <= 0 || math.IsNaN(guessesPerSecond) || math.IsNaN(entropyBits) {
+		return maxDuration
+	}
+
+	seconds := math.Exp2(entropyBits-1) / guessesPerSecond
+	nanos := seconds * float64(time.Second)
+	if nanos >= float64(maxDuration) {
+		return maxDuration
+	}
+	return time.Duration(nanos)
+}
+
+// The entropy thresholds of the EntropyRating bands, in bits. They are
+// variables so an application with its own policy can move them, e.g.
+// raise RatingStrongBits to 80; set them before rating anything, as
+// EntropyRating reads them without synchronization, and keep them in
+// increasing order.
+var (
+	// RatingFairBits is where "fair" starts: SecurityLow's 50 bits,
+	// enough for accounts an attacker can only guess at online.
+	RatingFairBits = 50.0
+	// RatingStrongBits is where "strong" starts: 70 bits, the CLI's
+	// recommended minimum, out of practical reach of offline attacks on
+	// leaked hashes.
+	RatingStrongBits = 70.0
+	// RatingExcellentBits is where "excellent" starts: SecurityHigh's 100
+	// bits.
+	RatingExcellentBits = 100.0
+)
+
+// EntropyRating returns a label for entropyBits, for UIs to show next to
+// the number or to drive a strength meter: "weak" below RatingFairBits,
+// then "fair", "strong" from RatingStrongBits and "excellent" from
+// RatingExcellentBits:
+//
+//	bits := diceware.EntropyForLanguage(6, diceware.LanguageEnglish)
+//	fmt.Printf("%.1f bits (%s)\n", bits, diceware.EntropyRating(bits)) // 77.5 bits (strong)
+func EntropyRating(entropyBits float64) string {
+	switch {
+	case entropyBits >= RatingExcellentBits:
+		return "excellent"
+	case entropyBits >= RatingStrongBits:
+		return "strong"
+	case entropyBits >= RatingFairBits:
+		return "fair"
+	}
+	return "weak"
+}
@@ -0,0 +1,73 @@
+package diceware
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestEstimateCrackTime(t *testing.T) {
+	const maxDuration = time.Duration(math.MaxInt64)
+
+	tests := []struct {
+		name             string
+		entropyBits      float64
+		guessesPerSecond float64
+		want             time.Duration
+	}{
+		{"1 bit at 1/s", 1, 1, time.Second},
+		{"10 bits at 512/s", 10, 512, time.Second},
+		{"20 bits at 1/s", 20, 1, 524288 * time.Second},
+		{"40 bits offline GPU", 40, GuessRateOfflineGPU, time.Duration(math.Exp2(39) / 1e10 * 1e9)},
+		{"saturates", 100, 1, maxDuration},
+		{"6 words offline ASIC saturates", Entropy(6), GuessRateOfflineASIC, maxDuration},
+		{"zero rate", 10, 0, maxDuration},
+		{"negative rate", 10, -5, maxDuration},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EstimateCrackTime(tt.entropyBits, tt.guessesPerSecond); got != tt.want {
+				t.Errorf("EstimateCrackTime(%v, %v) = %v, want %v", tt.entropyBits, tt.guessesPerSecond, got, tt.want)
+			}
+		})
+	}
+
+	// Faster attackers must never take longer
+	bits := Entropy(4)
+	online := EstimateCrackTime(bits, GuessRateOnlineThrottled)
+	gpu := EstimateCrackTime(bits, GuessRateOfflineGPU)
+	asic := EstimateCrackTime(bits, GuessRateOfflineASIC)
+	if !(asic < gpu && gpu <= online) {
+		t.Errorf("crack times not ordered by attacker speed: online %v, GPU %v, ASIC %v", online, gpu, asic)
+	}
+}
+
+func TestEntropyRating(t *testing.T) {
+	tests := []struct {
+		bits float64
+		want string
+	}{
+		{0, "weak"},
+		{Entropy(3), "weak"},
+		{49.9, "weak"},
+		{50, "fair"},
+		{Entropy(4), "fair"},
+		{70, "strong"},
+		{Entropy(6), "strong"},
+		{Entropy(8), "excellent"},
+		{math.NaN(), "weak"},
+	}
+	for _, tt := range tests {
+		if got := EntropyRating(tt.bits); got != tt.want {
+			t.Errorf("EntropyRating(%f) = %q, want %q", tt.bits, got, tt.want)
+		}
+	}
+
+	// The bands can be moved
+	defer func(old float64) { RatingStrongBits = old }(RatingStrongBits)
+	RatingStrongBits = 80
+	if got := EntropyRating(Entropy(6)); got != "fair" {
+		t.Errorf("EntropyRating(%f) with RatingStrongBits = 80 is %q, want fair", Entropy(6), got)
+	}
+}
@@ -0,0 +1,160 @@
+package diceware
+
+import (
+	"strings"
+	"unicode"
+)
+
+// consonantDigraphs are the letter pairs Syllables keeps in one syllable,
+// e.g. the "sh" of "wash-board".
+var consonantDigraphs = []string{"ch", "ck", "gh", "ng", "ph", "qu", "sh", "th", "wh"}
+
+// consonantBlends are the clusters that start a syllable together when a
+// longer run of consonants is split, e.g. the "tr" of "con-trol".
+var consonantBlends = []string{
+	"bl", "br", "cl", "cr", "dr", "fl", "fr", "gl", "gr", "pl", "pr",
+	"sc", "sk", "sl", "sm", "sn", "sp", "st", "sw", "tr", "tw",
+	"scr", "spl", "spr", "str",
+}
+
+// Syllables splits an English word into syllables for pronunciation hints,
+// e.g. "washboard" into "wash" and "board". It is a rule of thumb, not a
+// dictionary: every run of vowels is a syllable (a silent final "e" or
+// "ed" aside, "y" after a consonant counting as a vowel), and the
+// consonants between two runs are shared out, one to the next syllable,
+// or a blend like "tr" when there are three or more ("ck" and "ng" end a
+// syllable rather than start one). It does get words wrong, so show its
+// output as a reading aid next to the passphrase, never in place of it.
+// The syllables keep the word's case and, joined, give back word; a word
+// with no vowels is a single syllable.
+func Syllables(word string) []string {
+	letters := []rune(word)
+	lower := []rune(strings.ToLower(word))
+	if len(lower) != len(letters) {
+		return []string{word}
+	}
+
+	// Find the runs of vowels as [start, end) pairs.
+	var runs [][2]int
+	for i := 0; i < len(lower); {
+		if !isVowelAt(lower, i) {
+			i++
+			continue
+		}
+		start := i
+		for i < len(lower) && isVowelAt(lower, i) {
+			i++
+		}
+		runs = append(runs, [2]int{start, i})
+	}
+	runs = dropSilentEnding(lower, runs)
+	if len(runs) < 2 {
+		return []string{word}
+	}
+
+	var syllables []string
+	start := 0
+	for k := 1; k < len(runs); k++ {
+		from, to := runs[k-1][1], runs[k][0]
+		cut := syllableBreak(lower, from, to)
+		switch {
+		case cut+2 <= to && hasPair([]string{"ck", "ng"}, lower[cut:cut+2]):
+			// "chick-en", "long-est"
+			cut += 2
+		case cut < to && lower[cut] == 'w' && (lower[cut-1] == 'e' || lower[cut-1] == 'o'):
+			// "view-ing", "throw-a-way"
+			cut++
+		}
+		syllables = append(syllables, string(letters[start:cut]))
+		start = cut
+	}
+	return append(syllables, string(letters[start:]))
+}
+
+// SyllableHint returns word with its Syllables joined by hyphens, e.g.
+// "wash-board", the form to show next to a passphrase that has to be read
+// out. Like Syllables it is only a hint: it is not part of the passphrase.
+func SyllableHint(word string) string {
+	return strings.Join(Syllables(word), "-")
+}
+
+// isVowelAt reports whether lower[i] is a vowel: a, e, i, o, u, or a y
+// that follows a consonant ("dry", but not "yank" or "play").
+func isVowelAt(lower []rune, i int) bool {
+	switch lower[i] {
+	case 'a', 'e', 'i', 'o', 'u':
+		return true
+	case 'y':
+		return i > 0 && unicode.IsLetter(lower[i-1]) && !isVowelAt(lower, i-1)
+	}
+	return false
+}
+
+// dropSilentEnding removes the last vowel run when it's a silent final "e"
+// ("dosage") or the "e" of an "ed" not after t or d ("punctured"), but
+// keeps the "e" of a consonant + "le" ending ("table").
+func dropSilentEnding(lower []rune, runs [][2]int) [][2]int {
+	if len(runs) < 2 {
+		return runs
+	}
+	last := runs[len(runs)-1]
+	if last[1]-last[0] != 1 || lower[last[0]] != 'e' {
+		return runs
+	}
+	n := len(lower)
+	switch {
+	case last[1] == n:
+		if n >= 3 && lower[n-2] == 'l' && !isVowelAt(lower, n-3) {
+			return runs
+		}
+	case last[1] == n-1 && lower[n-1] == 'd':
+		if n >= 3 && (lower[n-3] == 't' || lower[n-3] == 'd') {
+			return runs
+		}
+	default:
+		return runs
+	}
+	return runs[:len(runs)-1]
+}
+
+// syllableBreak returns where to split the consonants lower[from:to]
+// between two vowel runs. A digraph counts as one consonant.
+func syllableBreak(lower []rune, from, to int) int {
+	var units []int // start of each consonant
+	for i := from; i < to; i++ {
+		units = append(units, i)
+		if i+1 < to && hasPair(consonantDigraphs, lower[i:i+2]) {
+			i++
+		}
+	}
+	switch {
+	case len(units) == 0:
+		return to
+	case len(units) == 1:
+		// One consonant starts the next syllable: "do-sage".
+		return units[0]
+	case len(units) >= 4 && to-units[len(units)-3] == 3 && hasPair(consonantBlends, lower[units[len(units)-3]:to]):
+		// So does a three letter blend: "con-strict".
+		return units[len(units)-3]
+	case len(units) >= 3 && to-units[len(units)-2] == 2 && hasPair(consonantBlends, lower[units[len(units)-2]:to]):
+		// A blend starts the next syllable: "con-trol".
+		return units[len(units)-2]
+	}
+	// Otherwise only the last consonant does: "gam-ma", "punc-tured".
+	// A consonant + "le" ending is its own syllable: "ta-ble".
+	if to == len(lower)-1 && lower[to] == 'e' && lower[to-1] == 'l' {
+		return units[len(units)-2]
+	}
+	return units[len(units)-1]
+}
+
+// hasPair reports whether the letters pair are one of pairs.
+func hasPair(pairs []string, pair []rune) bool {
+	s := string(pair)
+	for _, p := range pairs {
+		if p == s {
+			return true
+		}
+	}
+	return false
+}
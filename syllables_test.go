@@ -0,0 +1,46 @@
+package diceware
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSyllables(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"washboard", "wash-board"},
+		{"Puritan", "Pu-ri-tan"},
+		{"gamma", "gam-ma"},
+		{"dosage", "do-sage"},
+		{"punctured", "punc-tured"},
+		{"elongated", "e-long-a-ted"},
+		{"table", "ta-ble"},
+		{"pickle", "pick-le"},
+		{"control", "con-trol"},
+		{"constrict", "con-strict"},
+		{"viewing", "view-ing"},
+		{"sadly", "sad-ly"},
+		{"yanking", "yan-king"},
+		{"cake", "cake"},
+		{"rhythm", "rhythm"},
+		{"T-bone", "T-bone"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := SyllableHint(tt.word); got != tt.want {
+			t.Errorf("SyllableHint(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+
+	// Joined, the syllables always give back the word.
+	for _, w := range builtin(LanguageEnglish).words {
+		if w == "" {
+			continue
+		}
+		if got := strings.Join(Syllables(w), ""); got != w {
+			t.Fatalf("Syllables(%q) joined = %q, want the word", w, got)
+		}
+	}
+}
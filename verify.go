@@ -0,0 +1,262 @@
+package diceware
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// VerifyPassphrase reports whether passphrase has the shape of one this
+// library generates for lang: exactly wordCount words, each of which is a
+// usable word of the language's wordlist (either list for LanguageMixed).
+// Any non-letter separator between the words is accepted, including none
+// at all, and so is any capitalization: words are split at capital letters
+// when that works, and matched against the wordlist like SplitPassphrase
+// otherwise. Returns an error if wordCount is less than 1 or lang is
+// unsupported.
+func VerifyPassphrase(passphrase string, wordCount int, lang Language) (bool, error) {
+	if wordCount < 1 {
+		return false, invalidWordCount(wordCount)
+	}
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return false, err
+	}
+
+	if words, ok := splitCapitalized(passphrase); ok && len(words) == wordCount && allInWordlists(lists, words) {
+		return true, nil
+	}
+	_, ok := segment(passphrase, lists, wordCount)
+	return ok, nil
+}
+
+// SplitPassphrase splits passphrase back into the words of lang's wordlist
+// (either list for LanguageMixed) it is made of, without relying on
+// capitalization or separators: "correcthorsebattery" splits as well as
+// "Correct-Horse-Battery". Words are returned as they appear in passphrase,
+// without the separators, which may be any non-letters.
+//
+// Words are matched longest first, backtracking when the rest of the
+// passphrase can't be split, so a word that merely starts with another
+// (e.g. "abacus" and "aba") is kept whole. A passphrase without separators
+// can occasionally be split more than one way; the first split found is
+// returned. Returns an error if lang is unsupported, or ErrWordNotFound if
+// passphrase isn't made of words of the wordlist.
+func SplitPassphrase(passphrase string, lang Language) ([]string, error) {
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return nil, err
+	}
+	words, ok := segment(passphrase, lists, 0)
+	if !ok {
+		return nil, fmt.Errorf("%w: the passphrase can't be split into words of the wordlist", ErrWordNotFound)
+	}
+	return words, nil
+}
+
+// SecureEqual reports whether a and b are the same passphrase in time that
+// doesn't depend on their contents, for checking user input against a
+// stored passphrase without the timing leak of ==, which returns as soon
+// as a byte differs. Unlike subtle.ConstantTimeCompare it doesn't return
+// early when the lengths differ either: both are hashed with SHA-256 and
+// the digests compared, so the time only depends on the lengths in 64-byte
+// blocks. The comparison is exact, capitalization and separators included.
+//
+// Prefer storing a slow password hash (bcrypt, argon2) of the passphrase
+// over the passphrase itself where possible.
+func SecureEqual(a, b string) bool {
+	ha, hb := sha256.Sum256([]byte(a)), sha256.Sum256([]byte(b))
+	return subtle.ConstantTimeCompare(ha[:], hb[:]) == 1
+}
+
+// allInWordlists reports whether every one of words is a usable word of
+// one of lists.
+func allInWordlists(lists []*Wordlist, words []string) bool {
+	for _, word := range words {
+		if !inAnyWordlist(lists, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// inAnyWordlist reports whether word is a usable word of one of lists.
+func inAnyWordlist(lists []*Wordlist, word string) bool {
+	for _, wl := range lists {
+		if _, ok := wl.lookupWord(word); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// splitCapitalized splits a passphrase into words, each starting at an
+// upper or title case letter and running up to the next one. Trailing
+// non-letters (the separator) are trimmed off each word, while non-letters
+// inside a word, like the hyphen in "Drop-down", are kept. ok is false if
+// the passphrase doesn't start with a capitalized word.
+func splitCapitalized(passphrase string) (words []string, ok bool) {
+	passphrase = strings.TrimSpace(passphrase)
+	if passphrase == "" {
+		return nil, false
+	}
+
+	start := -1
+	flush := func(end int) {
+		word := strings.TrimRightFunc(passphrase[start:end], func(r rune) bool {
+			return !unicode.IsLetter(r)
+		})
+		words = append(words, word)
+	}
+	for i, r := range passphrase {
+		if !unicode.IsUpper(r) && !unicode.IsTitle(r) {
+			if start < 0 {
+				return nil, false
+			}
+			continue
+		}
+		if start >= 0 {
+			flush(i)
+		}
+		start = i
+	}
+	flush(len(passphrase))
+	return words, true
+}
+
+// capitalizedSplits calls fn with each way of splitting passphrase into
+// usable words of lists, optionally separated by runs of non-letters, in
+// which every word starts with a capital letter or a non-letter, the way
+// the default capitalization writes them, until fn returns true. It
+// reports whether fn did. Unlike splitCapitalized it keeps words with no
+// letter to capitalize, like Reinhold's "100", as words of their own, and
+// since those can be read more than one way ("19" or "1" and "9"), it tries
+// them all.
+func capitalizedSplits(passphrase string, lists []*Wordlist, separators bool, fn func(words []string) bool) bool {
+	runes := []rune(toNFC(strings.TrimSpace(passphrase)))
+	longest := longestWord(lists)
+	startsWord := func(r rune) bool {
+		return !unicode.IsLetter(r) || unicode.IsUpper(r) || unicode.IsTitle(r)
+	}
+
+	// dead remembers positions with no split of the rest at all, which
+	// are the same whatever fn says
+	dead := make(map[[2]int]bool)
+	calls := 0
+	var words []string
+	var split func(i int) bool
+	split = func(i int) bool {
+		if i == len(runes) {
+			calls++
+			return len(words) > 0 && fn(words)
+		}
+		key := [2]int{i, min(len(words), 1)}
+		if dead[key] {
+			return false
+		}
+		before := calls
+		last := i
+		if separators && len(words) > 0 {
+			for last < len(runes) && !unicode.IsLetter(runes[last]) {
+				last++
+			}
+		}
+		for start := i; start <= last && start < len(runes); start++ {
+			if !startsWord(runes[start]) {
+				continue
+			}
+			for end := start + 1; end <= min(start+longest, len(runes)); end++ {
+				if end < len(runes) && !startsWord(runes[end]) {
+					continue
+				}
+				word := string(runes[start:end])
+				if !inAnyWordlist(lists, word) {
+					continue
+				}
+				words = append(words, word)
+				if split(end) {
+					return true
+				}
+				words = words[:len(words)-1]
+			}
+		}
+		if calls == before {
+			dead[key] = true
+		}
+		return false
+	}
+	return split(0)
+}
+
+// segment splits passphrase into usable words of lists, separated by runs
+// of non-letters or nothing at all, with exactly wordCount words if
+// wordCount is positive. Non-letters are taken as part of a word where one
+// matches, like Reinhold's "100", and as a separator otherwise. Matching
+// ignores case; longer words are tried first, and positions already known
+// not to split are remembered so backtracking stays linear in practice. ok
+// is false if there's no such split, including when passphrase starts or
+// ends with a separator.
+func segment(passphrase string, lists []*Wordlist, wordCount int) (words []string, ok bool) {
+	runes := []rune(toNFC(strings.TrimSpace(passphrase)))
+	if len(runes) == 0 {
+		return nil, false
+	}
+	longest := longestWord(lists)
+
+	failed := make(map[[2]int]bool)
+	var split func(i int) bool
+	split = func(i int) bool {
+		if i == len(runes) {
+			return wordCount <= 0 || len(words) == wordCount
+		}
+		key := [2]int{i, len(words)}
+		if failed[key] || wordCount > 0 && len(words) == wordCount {
+			return false
+		}
+
+		// Every word but the first may follow a separator, which is tried
+		// shortest first
+		last := i
+		if len(words) > 0 {
+			for last < len(runes) && !unicode.IsLetter(runes[last]) {
+				last++
+			}
+		}
+		for start := i; start <= last && start < len(runes); start++ {
+			for n := min(longest, len(runes)-start); n > 0; n-- {
+				word := string(runes[start : start+n])
+				if !inAnyWordlist(lists, word) {
+					continue
+				}
+				words = append(words, word)
+				if split(start + n) {
+					return true
+				}
+				words = words[:len(words)-1]
+			}
+		}
+		failed[key] = true
+		return false
+	}
+	if !split(0) {
+		return nil, false
+	}
+	return words, true
+}
+
+// longestWord returns the length in runes of the longest usable word of
+// lists.
+func longestWord(lists []*Wordlist) int {
+	longest := 0
+	for _, wl := range lists {
+		for _, word := range wl.words {
+			if word != "" && wl.accepts(word) {
+				longest = max(longest, utf8.RuneCountInString(word))
+			}
+		}
+	}
+	return longest
+}
@@ -0,0 +1,152 @@
+package diceware
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestVerifyPassphrase(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		for _, sep := range []string{"", "-", " ", "_", "1"} {
+			passphrase, err := GenerateWithLanguageAndSeparator(6, lang, sep)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ok, err := VerifyPassphrase(passphrase, 6, lang)
+			if err != nil || !ok {
+				t.Errorf("VerifyPassphrase(%q, 6, %v) = %v, %v, want true", passphrase, lang, ok, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		name       string
+		passphrase string
+		wordCount  int
+		lang       Language
+		want       bool
+	}{
+		{"hyphenated word", "Drop-down-Abacus", 2, LanguageEnglish, true},
+		{"wrong count", "AbacusAbdomen", 3, LanguageEnglish, false},
+		{"unknown word", "AbacusQwxzy", 2, LanguageEnglish, false},
+		{"lowercase", "abacusabdomen", 2, LanguageEnglish, true},
+		{"uppercase with separator", "ABACUS-ABDOMEN", 2, LanguageEnglish, true},
+		{"lowercase wrong count", "abacusabdomen", 3, LanguageEnglish, false},
+		{"leading separator", "-AbacusAbdomen", 2, LanguageEnglish, false},
+		{"Romanian word in English", "AbajurAbacus", 2, LanguageEnglish, false},
+		{"Romanian word in mixed", "AbajurAbacus", 2, LanguageMixed, true},
+		{"trailing digits", "Abajur0", 2, LanguageRomanian, false},
+		{"empty", "", 1, LanguageEnglish, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := VerifyPassphrase(tt.passphrase, tt.wordCount, tt.lang)
+			if err != nil {
+				t.Fatalf("VerifyPassphrase() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("VerifyPassphrase(%q) = %v, want %v", tt.passphrase, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := VerifyPassphrase("Abacus", 0, LanguageEnglish); err == nil {
+		t.Error("VerifyPassphrase() with word count 0 should return an error")
+	}
+	if _, err := VerifyPassphrase("Abacus", 1, Language(99)); err == nil {
+		t.Error("VerifyPassphrase() with an unsupported language should return an error")
+	}
+}
+
+func TestSplitCapitalized(t *testing.T) {
+	tests := []struct {
+		in     string
+		want   []string
+		wantOK bool
+	}{
+		{"ColtDefaultArousal", []string{"Colt", "Default", "Arousal"}, true},
+		{"Colt-Drop-down-Arousal", []string{"Colt", "Drop-down", "Arousal"}, true},
+		{"Colt  Default", []string{"Colt", "Default"}, true},
+		{"Țară Șarpe", []string{"Țară", "Șarpe"}, true},
+		{"ǅepǈubav", []string{"ǅep", "ǈubav"}, true},
+		{"colt", nil, false},
+		{"", nil, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := splitCapitalized(tt.in)
+		if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitCapitalized(%q) = %v, %v, want %v, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestSplitPassphrase(t *testing.T) {
+	tests := []struct {
+		in   string
+		lang Language
+		want []string
+	}{
+		{"abacusabdomen", LanguageEnglish, []string{"abacus", "abdomen"}},
+		{"ABACUS ABDOMEN", LanguageEnglish, []string{"ABACUS", "ABDOMEN"}},
+		{"AbacusAbdomen", LanguageEnglish, []string{"Abacus", "Abdomen"}},
+		{"drop-down-abacus", LanguageEnglish, []string{"drop-down", "abacus"}},
+		{"abajur.abacus", LanguageMixed, []string{"abajur", "abacus"}},
+		{"abacus", LanguageEnglish, []string{"abacus"}},
+	}
+	for _, tt := range tests {
+		got, err := SplitPassphrase(tt.in, tt.lang)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("SplitPassphrase(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	// Generated passphrases split back into words, without any cue
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		for _, mode := range []CapitalizationMode{CapNone, CapUpper} {
+			passphrase, err := GenerateWithOptions(6, WithLanguage(lang), WithCapitalization(mode))
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := SplitPassphrase(passphrase, lang)
+			if err != nil {
+				t.Errorf("SplitPassphrase(%q) error = %v", passphrase, err)
+				continue
+			}
+			if strings.Join(got, "") != passphrase {
+				t.Errorf("SplitPassphrase(%q) = %q", passphrase, got)
+			}
+		}
+	}
+
+	for _, in := range []string{"", "qwxzy", "abacus-", "-abacus", "abacusq"} {
+		if _, err := SplitPassphrase(in, LanguageEnglish); !errors.Is(err, ErrWordNotFound) {
+			t.Errorf("SplitPassphrase(%q) error = %v, want ErrWordNotFound", in, err)
+		}
+	}
+	if _, err := SplitPassphrase("abacus", Language(99)); err == nil {
+		t.Error("SplitPassphrase() with an unsupported language should return an error")
+	}
+}
+
+func TestSecureEqual(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"ColtDefaultArousal", "ColtDefaultArousal", true},
+		{"", "", true},
+		{"ColtDefaultArousal", "ColtDefaultArousaL", false},
+		{"ColtDefaultArousal", "coltdefaultarousal", false},
+		{"ColtDefaultArousal", "ColtDefault", false},
+		{"ColtDefaultArousal", "", false},
+	}
+	for _, tt := range tests {
+		if got := SecureEqual(tt.a, tt.b); got != tt.want {
+			t.Errorf("SecureEqual(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
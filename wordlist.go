@@ -0,0 +1,675 @@
+package diceware
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Wordlist is a parsed Diceware wordlist mapping dice rolls (e.g. "43434")
+// to words, stored as a slice indexed by the roll's base-6 value. The
+// built-in lists use five dice per word; custom lists may use any number
+// (see DiceCount). The built-in lists are available through
+// WordlistByLanguage; custom lists can be created with NewWordlist.
+//
+// A Wordlist is immutable once created and safe to share between
+// goroutines.
+type Wordlist struct {
+	name string
+
+	// lang is the Language the list is registered as, or LanguageUnknown.
+	lang Language
+
+	// words holds rollCount(dice) entries indexed by rollToIndex, ""
+	// marking rolls without one, so generation can go straight from the
+	// dice to a word without building a roll string or hashing it.
+	words []string
+
+	// dice is the number of dice rolled per word.
+	dice int
+
+	// bits is the number of random bits indexing a list that isn't rolled
+	// with dice, like BIP39's 2,048 words (11 bits); its words then hold
+	// 2^bits entries and dice is 0. It is 0 for dice lists.
+	bits int
+
+	// accept reports whether an entry may appear in a passphrase. Rolls
+	// landing on an entry it rejects are rerolled during generation (e.g.
+	// Romanian's numeric/symbol filler entries). nil accepts everything.
+	accept func(word string) bool
+
+	// size is the number of entries accept lets through. This, not the
+	// number of entries, is the count that must be used for entropy/size
+	// reporting.
+	size int
+
+	// asciiOnly marks lists whose words must all be ASCII (checked by
+	// ValidateWordlist).
+	asciiOnly bool
+
+	// minLen and maxLen are the shortest and longest usable word, in
+	// characters, used to reject impossible length constraints up front.
+	minLen, maxLen int
+
+	// probs holds each entry's probability for a weighted list (see
+	// NewWeightedWordlist), in roll order, and cum their running sums for
+	// sampling. Both are nil for ordinary lists, whose entries are all
+	// equally likely.
+	probs, cum []float64
+
+	// skipped holds an error per malformed line LoadWordlistLenient
+	// skipped, see Skipped.
+	skipped []error
+
+	// reverse maps each usable word, lowercased, back to its roll index.
+	// Only verification needs it, so it is built on first use by
+	// lookupWord.
+	reverseOnce sync.Once
+	reverse     map[string]int
+}
+
+// newWordlist wraps already-validated, roll-indexed words (see
+// readWordlist) in a Wordlist, counting the entries that accept lets
+// through. The number of dice follows from len(words), a power of 6.
+func newWordlist(name string, words []string, accept func(string) bool) *Wordlist {
+	wl := &Wordlist{name: name, lang: LanguageUnknown, words: words, dice: diceForRolls(len(words)), accept: accept}
+	for _, word := range words {
+		if word == "" || !wl.accepts(word) {
+			continue
+		}
+		wl.size++
+		n := utf8.RuneCountInString(word)
+		if wl.size == 1 || n < wl.minLen {
+			wl.minLen = n
+		}
+		if n > wl.maxLen {
+			wl.maxLen = n
+		}
+	}
+	return wl
+}
+
+// NewWordlist creates a Wordlist from a map of dice rolls to words, for use
+// with GenerateFromWordlists or WithWordlists. Every key must be a valid
+// roll of the same number of dice (e.g. 5 digits, each 1-6, or 4 for a
+// short list of 1,296 words) and every word must be non-empty. The map is
+// copied, so later changes to it don't affect the Wordlist. Words are
+// normalized to NFC, like those of a parsed wordlist.
+//
+// The list doesn't have to cover all 6^n rolls: rolls without an entry are
+// rerolled during generation, so entropy is based on the number of entries.
+func NewWordlist(name string, entries map[string]string) (*Wordlist, error) {
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: %q has no entries", ErrEmptyWordlist, name)
+	}
+
+	dice := 0
+	for roll := range entries {
+		dice = len(roll)
+		break
+	}
+	if dice < 1 || dice > maxDiceCount {
+		return nil, fmt.Errorf("wordlist %q has %d dice per roll, want 1 to %d", name, dice, maxDiceCount)
+	}
+
+	words := make([]string, rollCount(dice))
+	for roll, word := range entries {
+		i, ok := rollToIndex(roll, dice)
+		if !ok {
+			return nil, fmt.Errorf("wordlist %q has invalid dice roll %q (expected %d digits between 1-6)", name, roll, dice)
+		}
+		if word == "" {
+			return nil, fmt.Errorf("wordlist %q has an empty word for dice roll %s", name, roll)
+		}
+		words[i] = toNFC(word)
+	}
+
+	return newWordlist(name, words, nil), nil
+}
+
+// NewWeightedWordlist creates a Wordlist whose words aren't equally likely:
+// each word is drawn with probability proportional to its weight, e.g. to
+// experiment with frequency-weighted lists. Weighted lists work anywhere a
+// Wordlist does, but they are no longer Diceware: their words are sampled
+// with crypto/rand rather than rolled, the rolls reported for them (see
+// GenerateWithRollsAndOptions) are merely the position of the word in
+// sorted order, and the entropy reported by EntropyWithOptions is the
+// Shannon entropy of the distribution, which is less than log2 of the
+// number of words unless all weights are equal.
+//
+// Every weight must be positive and finite, and every word non-empty.
+// Returns an error otherwise, or if there are more than 6^6 words.
+func NewWeightedWordlist(name string, weights map[string]float64) (*Wordlist, error) {
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("%w: %q has no entries", ErrEmptyWordlist, name)
+	}
+	if len(weights) > rollCount(maxDiceCount) {
+		return nil, fmt.Errorf("wordlist %q has %d words, more than the %d that %d dice can index", name, len(weights), rollCount(maxDiceCount), maxDiceCount)
+	}
+
+	sorted := make([]string, 0, len(weights))
+	total := 0.0
+	for word, w := range weights {
+		if word == "" {
+			return nil, fmt.Errorf("wordlist %q has an empty word", name)
+		}
+		if !(w > 0) || math.IsInf(w, 1) {
+			return nil, fmt.Errorf("wordlist %q has invalid weight %v for %q (must be positive and finite)", name, w, word)
+		}
+		sorted = append(sorted, word)
+		total += w
+	}
+	sort.Strings(sorted)
+
+	dice := 1
+	for rollCount(dice) < len(sorted) {
+		dice++
+	}
+	words := make([]string, rollCount(dice))
+	probs := make([]float64, len(words))
+	cum := make([]float64, len(words))
+	sum := 0.0
+	for i, word := range sorted {
+		words[i] = toNFC(word)
+		probs[i] = weights[word] / total
+		sum += probs[i]
+		cum[i] = sum
+	}
+	for i := len(sorted); i < len(cum); i++ {
+		cum[i] = sum
+	}
+
+	wl := newWordlist(name, words, nil)
+	wl.probs, wl.cum = probs, cum
+	return wl, nil
+}
+
+// WordlistByLanguage returns the built-in wordlist for the specified
+// language. LanguageMixed isn't backed by a single list and returns an
+// error; pass both lists to GenerateFromWordlists instead.
+func WordlistByLanguage(lang Language) (*Wordlist, error) {
+	if lang == LanguageMixed {
+		return nil, errors.New("LanguageMixed combines several wordlists and has no single Wordlist")
+	}
+	if wl, ok := lookupWordlist(lang); ok {
+		return wl, nil
+	}
+	return nil, unsupportedLanguage(lang)
+}
+
+// WordAt returns the capitalized word for a single roll (e.g. "43434") in
+// the specified language's wordlist - the lookup behind every generated
+// word. LanguageMixed has no single wordlist and returns an error, as does
+// a roll that doesn't have one digit between 1-6 per die of the list (see
+// Wordlist.DiceCount) or that lands on an entry generation would reroll
+// (e.g. Romanian filler entries). LanguageBIP39English takes the word's
+// index instead, e.g. WordAt("0", LanguageBIP39English) returns "Abandon".
+func WordAt(roll string, lang Language) (string, error) {
+	wl, err := WordlistByLanguage(lang)
+	if err != nil {
+		return "", err
+	}
+	i, ok := wl.parseRoll(roll)
+	if !ok {
+		return "", fmt.Errorf("invalid dice roll %q (expected %s)", roll, wl.rollFormat())
+	}
+	word := wl.words[i]
+	if word == "" || !wl.accepts(word) {
+		return "", fmt.Errorf("%w: %s wordlist has no usable word for dice roll %s", ErrWordNotFound, wl.name, roll)
+	}
+	return capitalize(word), nil
+}
+
+// WordlistInfo describes a wordlist, so callers can introspect it instead of
+// hardcoding figures like 7,776 words or 12.925 bits per word.
+type WordlistInfo struct {
+	// Name is the human-readable name, e.g. "English".
+	Name string
+	// Size is the number of usable words, see Wordlist.Size.
+	Size int
+	// BitsPerWord is the entropy each word adds, log2(Size).
+	BitsPerWord float64
+	// DiceCount is the number of dice rolled per word, or 0 for a list
+	// indexed by random bits like LanguageBIP39English.
+	DiceCount int
+}
+
+// Info returns metadata about the wordlist. For a weighted list,
+// BitsPerWord is the Shannon entropy of its distribution.
+func (wl *Wordlist) Info() WordlistInfo {
+	bits := bitsForSize(wl.size)
+	if wl.probs != nil {
+		bits = drawBits([]*Wordlist{wl}, nil)
+	}
+	return WordlistInfo{
+		Name:        wl.name,
+		Size:        wl.size,
+		BitsPerWord: bits,
+		DiceCount:   wl.dice,
+	}
+}
+
+// WordlistInfoByLanguage returns metadata about the wordlist behind lang.
+// For LanguageMixed it describes the combined English and Romanian pool,
+// matching WordlistSizeByLanguage and EntropyForLanguage.
+func WordlistInfoByLanguage(lang Language) (WordlistInfo, error) {
+	if lang == LanguageMixed {
+		size := WordlistSizeByLanguage(lang)
+		return WordlistInfo{
+			Name:        "Mixed (English + Romanian)",
+			Size:        size,
+			BitsPerWord: bitsForSize(size),
+			DiceCount:   diceCount,
+		}, nil
+	}
+	wl, err := WordlistByLanguage(lang)
+	if err != nil {
+		return WordlistInfo{}, err
+	}
+	return wl.Info(), nil
+}
+
+// bitsForSize returns the entropy of a uniform pick among size words.
+func bitsForSize(size int) float64 {
+	if size < 2 {
+		return 0
+	}
+	return math.Log2(float64(size))
+}
+
+// Name returns the human-readable name of the wordlist, e.g. "English".
+func (wl *Wordlist) Name() string {
+	return wl.name
+}
+
+// DiceCount returns the number of dice rolled per word: 5 for the built-in
+// dice lists, whose 7,776 rolls are 6^5, or e.g. 4 for a 1,296-roll short
+// list. It is 0 for LanguageBIP39English, which isn't rolled with dice.
+func (wl *Wordlist) DiceCount() int {
+	return wl.dice
+}
+
+// Skipped returns an error for each malformed line LoadWordlistLenient
+// skipped while reading the list, in line order, each naming the line
+// number and what was wrong with it (a roll without a word, an invalid
+// roll or a duplicate one), so a lenient load can still be reported:
+//
+//	wl, err := diceware.LoadWordlistLenient("community", f)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//	for _, err := range wl.Skipped() {
+//	    log.Printf("skipped: %v", err)
+//	}
+//
+// It returns nil for lists loaded any other way, since those reject
+// malformed lines outright.
+func (wl *Wordlist) Skipped() []error {
+	return slices.Clone(wl.skipped)
+}
+
+// Size returns the number of usable words in the wordlist, i.e. the number
+// of dice rolls that actually produce a word during generation.
+func (wl *Wordlist) Size() int {
+	return wl.size
+}
+
+// Words returns the usable words of the list, sorted, e.g. for
+// autocomplete or spell-checking a typed passphrase. The slice is a fresh
+// copy the caller may modify.
+func (wl *Wordlist) Words() []string {
+	words := make([]string, 0, wl.size)
+	for _, word := range wl.words {
+		if word != "" && wl.accepts(word) {
+			words = append(words, word)
+		}
+	}
+	sort.Strings(words)
+	return words
+}
+
+// Words returns the usable words of the specified language's wordlist,
+// sorted, see Wordlist.Words. For LanguageMixed it returns both lists
+// merged, with words they share listed once. Returns nil for an
+// unsupported language.
+func Words(lang Language) []string {
+	lists, _, err := languageWordlists(lang, defaultMixedRatio)
+	if err != nil {
+		return nil
+	}
+	if len(lists) == 1 {
+		return lists[0].Words()
+	}
+
+	var words []string
+	for _, wl := range lists {
+		words = append(words, wl.Words()...)
+	}
+	sort.Strings(words)
+	return slices.Compact(words)
+}
+
+// IsASCII reports whether every usable word of the list is pure ASCII, for
+// systems with strict charset requirements, e.g. to decide whether a
+// loaded list needs WithASCIIFold. The LanguageEnglish, LanguageReinhold
+// and LanguageBIP39English lists are guaranteed ASCII: ValidateWordlist
+// reports a non-ASCII word in their embedded files.
+func (wl *Wordlist) IsASCII() bool {
+	for _, word := range wl.words {
+		if word != "" && wl.accepts(word) && !isASCII(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// DiffWordlists compares the usable words of two lists, returning the
+// words only a has and those only b has, each sorted, e.g. to check that
+// an update to a community list didn't drop or change entries by accident.
+// Words are compared exactly, case included; a word that merely moved to a
+// different roll isn't a difference. Both results are empty if the lists
+// have the same words.
+func DiffWordlists(a, b *Wordlist) (onlyA, onlyB []string) {
+	wordsA, wordsB := a.Words(), b.Words()
+	for len(wordsA) > 0 || len(wordsB) > 0 {
+		switch {
+		case len(wordsB) == 0 || (len(wordsA) > 0 && wordsA[0] < wordsB[0]):
+			onlyA = append(onlyA, wordsA[0])
+			wordsA = wordsA[1:]
+		case len(wordsA) == 0 || wordsB[0] < wordsA[0]:
+			onlyB = append(onlyB, wordsB[0])
+			wordsB = wordsB[1:]
+		default:
+			wordsA, wordsB = wordsA[1:], wordsB[1:]
+		}
+	}
+	return onlyA, onlyB
+}
+
+// accepts reports whether word may appear in a passphrase.
+func (wl *Wordlist) accepts(word string) bool {
+	return wl.accept == nil || wl.accept(word)
+}
+
+// HasUniquePrefixes reports whether every usable word of the list is
+// identified by its first n characters (ignoring case), so typeahead
+// tooling can complete a word after n keystrokes. Words shorter than n
+// characters count as their own prefix. Note that the EFF large wordlist
+// does not have this property for small n; EFF's short lists were built
+// for it.
+func (wl *Wordlist) HasUniquePrefixes(n int) bool {
+	return wl.CheckUniquePrefixes(n) == nil
+}
+
+// CheckUniquePrefixes is like HasUniquePrefixes but returns an error naming
+// two words that share a prefix, suitable for warning about a loaded list.
+func (wl *Wordlist) CheckUniquePrefixes(n int) error {
+	if n < 1 {
+		return fmt.Errorf("prefix length must be at least 1, got %d", n)
+	}
+
+	seen := make(map[string]string, wl.size)
+	for _, word := range wl.words {
+		if word == "" || !wl.accepts(word) {
+			continue
+		}
+		prefix := strings.ToLower(word)
+		if runes := []rune(prefix); len(runes) > n {
+			prefix = string(runes[:n])
+		}
+		if other, dup := seen[prefix]; dup {
+			return fmt.Errorf("%s wordlist words %q and %q share the %d-character prefix %q", wl.name, other, word, n, prefix)
+		}
+		seen[prefix] = word
+	}
+	return nil
+}
+
+// filter returns a copy of the list that also rejects the words keep
+// returns false for, sharing the words.
+func (wl *Wordlist) filter(keep func(word string) bool) *Wordlist {
+	derived := newWordlist(wl.name, wl.words, func(word string) bool {
+		return wl.accepts(word) && keep(word)
+	})
+	derived.lang = wl.lang
+	derived.dice, derived.bits = wl.dice, wl.bits
+	derived.probs, derived.cum = wl.probs, wl.cum
+	return derived
+}
+
+// roll picks the index of an entry of the list using random numbers from r:
+// by drawing one of its rolls at once (see rollUniform), reading its number
+// of bits for an indexed list, or for a weighted list by sampling its
+// distribution.
+func (wl *Wordlist) roll(r io.Reader) (int, error) {
+	if wl.bits == 0 && wl.probs == nil {
+		return rollUniform(r, rollCount(wl.dice))
+	}
+	return wl.rollEach(r)
+}
+
+// rollEach is like roll but rolls the dice of the list one at a time, a
+// byte per die, the way DeriveFromSeed always has so that the passphrases
+// it derives don't change.
+func (wl *Wordlist) rollEach(r io.Reader) (int, error) {
+	if wl.bits > 0 {
+		return readBits(r, wl.bits)
+	}
+	if wl.probs == nil {
+		return rollNDice(r, wl.dice)
+	}
+	u, err := randomUnitFloat(r)
+	if err != nil {
+		return 0, err
+	}
+	i := sort.Search(len(wl.cum), func(i int) bool { return wl.cum[i] > u })
+	if i == len(wl.cum) {
+		// Only reachable when rounding leaves the sum of probabilities
+		// just short of 1; fall back to the last word.
+		i = sort.SearchFloat64s(wl.cum, wl.cum[i-1])
+	}
+	return i, nil
+}
+
+// rollString returns the roll reported for entry i: its dice roll, e.g.
+// "43434", or its decimal index, e.g. "1024", for an indexed list.
+func (wl *Wordlist) rollString(i int) string {
+	if wl.bits > 0 {
+		return strconv.Itoa(i)
+	}
+	return indexToRoll(i, wl.dice)
+}
+
+// parseRoll is the inverse of rollString, reporting false for a roll that
+// isn't one of the list's.
+func (wl *Wordlist) parseRoll(roll string) (int, bool) {
+	if wl.bits > 0 {
+		i, err := strconv.Atoi(roll)
+		return i, err == nil && i >= 0 && i < len(wl.words) && roll == strconv.Itoa(i)
+	}
+	return rollToIndex(roll, wl.dice)
+}
+
+// rollFormat describes the rolls parseRoll accepts, for error messages.
+func (wl *Wordlist) rollFormat() string {
+	if wl.bits > 0 {
+		return fmt.Sprintf("an index between 0-%d", len(wl.words)-1)
+	}
+	return fmt.Sprintf("%d digits between 1-6", wl.dice)
+}
+
+// mass returns the probability that one roll of the list lands on a usable
+// entry for which keep (if non-nil) returns true.
+func (wl *Wordlist) mass(keep func(word string) bool) float64 {
+	if wl.probs == nil && keep == nil {
+		return float64(wl.size) / float64(len(wl.words))
+	}
+	n, p := 0, 0.0
+	for i, word := range wl.words {
+		if word != "" && wl.accepts(word) && (keep == nil || keep(word)) {
+			n++
+			if wl.probs != nil {
+				p += wl.probs[i]
+			}
+		}
+	}
+	if wl.probs == nil {
+		return float64(n) / float64(len(wl.words))
+	}
+	return p
+}
+
+// entryProb returns the probability that one roll of the list lands on
+// entry i.
+func (wl *Wordlist) entryProb(i int) float64 {
+	if wl.probs != nil {
+		return wl.probs[i]
+	}
+	return 1 / float64(len(wl.words))
+}
+
+// lookupWord returns the roll index (see rollToIndex) of a usable word of
+// the list, matched case-insensitively and whatever the normalization form.
+func (wl *Wordlist) lookupWord(word string) (index int, ok bool) {
+	wl.reverseOnce.Do(func() {
+		wl.reverse = make(map[string]int, wl.size)
+		for i, w := range wl.words {
+			if w != "" && wl.accepts(w) {
+				wl.reverse[strings.ToLower(w)] = i
+			}
+		}
+	})
+	index, ok = wl.reverse[strings.ToLower(toNFC(word))]
+	return index, ok
+}
+
+// drawWord picks one of lists, rolls its number of dice using random
+// numbers from r and returns the matching entry along with the roll and the
+// list it came from. List i is picked
+// with probability weights[i], or uniformly if weights is nil.
+//
+// Attempts landing on a roll the chosen list has no usable entry for are
+// discarded and redone from scratch, list pick included, for up to
+// maxDrawAttempts. That rejection sampling keeps every usable entry of every
+// list equally likely (scaled by its list's weight), which is what makes
+// the combined entropy calculation in bitsPerWord valid.
+func drawWord(r io.Reader, lists []*Wordlist, weights []float64) (word, roll string, list *Wordlist, err error) {
+	return drawWordWithin(r, lists, weights, maxDrawAttempts(lists, weights))
+}
+
+// drawWordWithin is drawWord giving up after maxAttempts attempts.
+func drawWordWithin(r io.Reader, lists []*Wordlist, weights []float64, maxAttempts int) (word, roll string, list *Wordlist, err error) {
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		list, err = pickWordlist(r, lists, weights)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		i, err := list.roll(r)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		word = list.words[i]
+		if word == "" || !list.accepts(word) {
+			// Filler entry (e.g. Romanian numbers/symbols) or a roll
+			// a partial custom list doesn't cover - reroll
+			continue
+		}
+
+		return word, list.rollString(i), list, nil
+	}
+
+	return "", "", nil, fmt.Errorf("%w: no usable word after %d attempts: %w", ErrWordNotFound, maxAttempts, ErrMaxAttempts)
+}
+
+// acceptRate returns the probability that a single drawWord attempt lands
+// on a usable entry, given the list selection weights (nil meaning uniform).
+func acceptRate(lists []*Wordlist, weights []float64) float64 {
+	rate := 0.0
+	for i, wl := range lists {
+		w := 1 / float64(len(lists))
+		if weights != nil {
+			w = weights[i]
+		}
+		rate += w * wl.mass(nil)
+	}
+	return rate
+}
+
+// maxDrawAttempts bounds the rerolls drawWord does before giving up. The
+// built-in lists accept nearly every roll, so 100 attempts is plenty, but a
+// sparse custom list may only cover a handful of its rolls; scale the
+// bound so that running out stays astronomically unlikely (about e^-40)
+// rather than failing on perfectly valid input.
+func maxDrawAttempts(lists []*Wordlist, weights []float64) int {
+	const minAttempts = 100 // Prevent infinite loops
+
+	rate := acceptRate(lists, weights)
+	if rate <= 0 {
+		return minAttempts
+	}
+	if n := int(math.Ceil(40 / rate)); n > minAttempts {
+		return n
+	}
+	return minAttempts
+}
+
+// pickWordlist selects one of lists using random numbers from r, with
+// probability weights[i] for list i or uniformly if weights is nil.
+func pickWordlist(r io.Reader, lists []*Wordlist, weights []float64) (*Wordlist, error) {
+	if len(lists) == 1 {
+		return lists[0], nil
+	}
+
+	if weights == nil {
+		n, err := rand.Int(r, big.NewInt(int64(len(lists))))
+		if err != nil {
+			return nil, fmt.Errorf("failed to select wordlist: %w", randomSourceError(err))
+		}
+		return lists[n.Int64()], nil
+	}
+
+	u, err := randomUnitFloat(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select wordlist: %w", err)
+	}
+	for i, w := range weights {
+		if u < w {
+			return lists[i], nil
+		}
+		u -= w
+	}
+	// Only reachable through floating point rounding when the weights sum
+	// to (almost exactly) 1; fall back to the last list that can be picked.
+	for i := len(weights) - 1; i >= 0; i-- {
+		if weights[i] > 0 {
+			return lists[i], nil
+		}
+	}
+	return lists[len(lists)-1], nil
+}
+
+// GenerateFromWordlists creates a passphrase with the specified number of
+// words drawn from any set of wordlists, joined with separator. For each
+// word one of the lists is picked uniformly at random and then rolled
+// against, which generalizes LanguageMixed to any number of languages:
+//
+//	en, _ := diceware.WordlistByLanguage(diceware.LanguageEnglish)
+//	ro, _ := diceware.WordlistByLanguage(diceware.LanguageRomanian)
+//	passphrase, err := diceware.GenerateFromWordlists(6, []*diceware.Wordlist{en, ro}, "-")
+//
+// Returns an error if wordCount is less than 1, if lists is empty or
+// contains nil, or if random number generation fails.
+func GenerateFromWordlists(wordCount int, lists []*Wordlist, separator string) (string, error) {
+	return GenerateWithOptions(wordCount, WithWordlists(lists...), WithSeparator(separator))
+}
@@ -0,0 +1,602 @@
+package diceware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// splitWords splits a passphrase of capitalized words joined by sep. Only a
+// sep followed by an uppercase letter starts a new word, so hyphenated EFF
+// words like "Yo-yo" stay whole when sep is "-".
+func splitWords(passphrase, sep string) []string {
+	var words []string
+	for _, part := range strings.Split(passphrase, sep) {
+		if r, _ := utf8.DecodeRuneInString(part); len(words) > 0 && !unicode.IsUpper(r) {
+			words[len(words)-1] += sep + part
+			continue
+		}
+		words = append(words, part)
+	}
+	return words
+}
+
+// testWordlist builds a small custom wordlist covering the first n rolls.
+// Every word starts with name followed by "w", so tests can tell which list
+// a generated word came from.
+func testWordlist(t *testing.T, name string, n int) *Wordlist {
+	t.Helper()
+	entries := make(map[string]string, n)
+	for i := 0; i < n; i++ {
+		entries[indexToRoll(i, diceCount)] = name + "w" + strings.Repeat("x", i%3) + indexToRoll(i, diceCount)
+	}
+	wl, err := NewWordlist(name, entries)
+	if err != nil {
+		t.Fatalf("NewWordlist() error = %v", err)
+	}
+	return wl
+}
+
+func TestNewWordlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries map[string]string
+		wantErr bool
+	}{
+		{"valid", map[string]string{"11111": "alpha", "11112": "beta"}, false},
+		{"empty", map[string]string{}, true},
+		{"invalid roll", map[string]string{"11117": "alpha"}, true},
+		{"four dice", map[string]string{"1111": "alpha", "6666": "beta"}, false},
+		{"mixed dice", map[string]string{"1111": "alpha", "11111": "beta"}, true},
+		{"too many dice", map[string]string{"1111111": "alpha"}, true},
+		{"empty word", map[string]string{"11111": ""}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wl, err := NewWordlist("test", tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewWordlist() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && wl.Size() != len(tt.entries) {
+				t.Errorf("Size() = %d, want %d", wl.Size(), len(tt.entries))
+			}
+		})
+	}
+
+	// The entries map is copied
+	entries := map[string]string{"11111": "alpha"}
+	wl, err := NewWordlist("copy", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries["11112"] = "beta"
+	if wl.Size() != 1 {
+		t.Errorf("Size() = %d after mutating the source map, want 1", wl.Size())
+	}
+}
+
+// TestBuiltinWordlists checks that every entry in the builtinWordlists
+// table is parsed, complete, and reachable through the language lookups
+func TestBuiltinWordlists(t *testing.T) {
+	for _, b := range builtinWordlists {
+		t.Run(b.name, func(t *testing.T) {
+			wl, err := WordlistByLanguage(b.lang)
+			if err != nil {
+				t.Fatalf("WordlistByLanguage(%v) error = %v", b.lang, err)
+			}
+			if wl.Name() != b.name {
+				t.Errorf("Name() = %q, want %q", wl.Name(), b.name)
+			}
+			if err := ValidateWordlist(b.lang); err != nil {
+				t.Errorf("ValidateWordlist(%v) error = %v", b.lang, err)
+			}
+			if WordlistSizeByLanguage(b.lang) != wl.Size() {
+				t.Errorf("WordlistSizeByLanguage(%v) = %d, want %d", b.lang, WordlistSizeByLanguage(b.lang), wl.Size())
+			}
+			if _, err := GenerateWithLanguage(4, b.lang); err != nil {
+				t.Errorf("GenerateWithLanguage(4, %v) error = %v", b.lang, err)
+			}
+		})
+	}
+}
+
+func TestWordlistByLanguage(t *testing.T) {
+	tests := []struct {
+		lang     Language
+		wantName string
+		wantErr  bool
+	}{
+		{LanguageEnglish, "English", false},
+		{LanguageRomanian, "Romanian", false},
+		{LanguageMixed, "", true},
+		{Language(99), "", true},
+	}
+
+	for _, tt := range tests {
+		wl, err := WordlistByLanguage(tt.lang)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WordlistByLanguage(%v) error = %v, wantErr %v", tt.lang, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if wl.Name() != tt.wantName {
+			t.Errorf("WordlistByLanguage(%v).Name() = %q, want %q", tt.lang, wl.Name(), tt.wantName)
+		}
+		if wl.Size() != WordlistSizeByLanguage(tt.lang) {
+			t.Errorf("WordlistByLanguage(%v).Size() = %d, want %d", tt.lang, wl.Size(), WordlistSizeByLanguage(tt.lang))
+		}
+	}
+}
+
+func TestGenerateFromWordlists(t *testing.T) {
+	en, _ := WordlistByLanguage(LanguageEnglish)
+	ro, _ := WordlistByLanguage(LanguageRomanian)
+	custom := testWordlist(t, "custom", 100)
+
+	passphrase, err := GenerateFromWordlists(6, []*Wordlist{en, ro, custom}, "-")
+	if err != nil {
+		t.Fatalf("GenerateFromWordlists() error = %v", err)
+	}
+	if got := len(splitWords(passphrase, "-")); got != 6 {
+		t.Errorf("GenerateFromWordlists() returned %d words, want 6", got)
+	}
+
+	// A partial custom list only ever yields its own entries
+	passphrase, err = GenerateFromWordlists(10, []*Wordlist{custom}, " ")
+	if err != nil {
+		t.Fatalf("GenerateFromWordlists() error = %v", err)
+	}
+	for _, word := range strings.Split(passphrase, " ") {
+		if !strings.HasPrefix(word, "Customw") {
+			t.Errorf("word %q is not from the custom wordlist", word)
+		}
+	}
+
+	for _, lists := range [][]*Wordlist{nil, {}, {en, nil}} {
+		if _, err := GenerateFromWordlists(4, lists, ""); err == nil {
+			t.Errorf("GenerateFromWordlists(%v) should return an error", lists)
+		}
+	}
+}
+
+// TestWordlistsEntropy checks that the entropy of uniformly mixed wordlists
+// is based on the combined number of usable words
+func TestWordlistsEntropy(t *testing.T) {
+	en, _ := WordlistByLanguage(LanguageEnglish)
+	ro, _ := WordlistByLanguage(LanguageRomanian)
+	custom := testWordlist(t, "custom", 1000)
+
+	tests := []struct {
+		name  string
+		lists []*Wordlist
+		words int
+	}{
+		{"custom only", []*Wordlist{custom}, 1000},
+		{"English and Romanian", []*Wordlist{en, ro}, en.Size() + ro.Size()},
+		{"all three", []*Wordlist{en, ro, custom}, en.Size() + ro.Size() + 1000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EntropyWithOptions(4, WithWordlists(tt.lists...))
+			want := 4 * math.Log2(float64(tt.words))
+			if math.Abs(got-want) > 1e-9 {
+				t.Errorf("EntropyWithOptions() = %f, want %f", got, want)
+			}
+		})
+	}
+}
+
+func TestWordAt(t *testing.T) {
+	tests := []struct {
+		roll    string
+		lang    Language
+		want    string
+		wantErr bool
+	}{
+		{"11111", LanguageEnglish, "Abacus", false},
+		{"24255", LanguageEnglish, "Drop-down", false},
+		{"11115", LanguageRomanian, "Abajur", false},
+		{"11112", LanguageReinhold, "A&p", false},
+		{"66666", LanguageReinhold, "@", false},
+		{"1111", LanguageEnglish, "", true},
+		{"11117", LanguageEnglish, "", true},
+		{"1111a", LanguageEnglish, "", true},
+		{"01111", LanguageEnglish, "", true},
+		{"11110", LanguageEnglish, "", true},
+		{"111111", LanguageEnglish, "", true},
+		{"11111", LanguageMixed, "", true},
+		{"11111", Language(99), "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := WordAt(tt.roll, tt.lang)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("WordAt(%q, %v) error = %v, wantErr %v", tt.roll, tt.lang, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("WordAt(%q, %v) = %q, want %q", tt.roll, tt.lang, got, tt.want)
+		}
+	}
+
+	// Romanian filler entries are reported rather than returned
+	ro, _ := WordlistByLanguage(LanguageRomanian)
+	for i, word := range ro.words {
+		if !ro.accepts(word) {
+			if _, err := WordAt(indexToRoll(i, diceCount), LanguageRomanian); err == nil {
+				t.Errorf("WordAt(%q) for filler entry %q should return an error", indexToRoll(i, diceCount), word)
+			}
+			break
+		}
+	}
+}
+
+func TestFourDiceWordlist(t *testing.T) {
+	var data strings.Builder
+	for i := 0; i < rollCount(4); i++ {
+		fmt.Fprintf(&data, "%s\tword%d\n", indexToRoll(i, 4), i)
+	}
+	wl, err := LoadWordlist("short", strings.NewReader(data.String()))
+	if err != nil {
+		t.Fatalf("LoadWordlist() error = %v", err)
+	}
+	if wl.DiceCount() != 4 || wl.Size() != 1296 || wl.Info().DiceCount != 4 {
+		t.Fatalf("DiceCount() = %d, Size() = %d, want 4 dice and 1296 words", wl.DiceCount(), wl.Size())
+	}
+	if err := validateWordlist("short", wl.words, true); err != nil {
+		t.Errorf("validateWordlist() error = %v", err)
+	}
+
+	_, words, err := GenerateWithRolledWords(8, WithWordlists(wl))
+	if err != nil {
+		t.Fatalf("GenerateWithRolledWords() error = %v", err)
+	}
+	for _, w := range words {
+		if len(w.Roll) != 4 {
+			t.Errorf("roll %q for %q, want 4 dice", w.Roll, w.Word)
+		}
+	}
+	if got, want := EntropyWithOptions(1, WithWordlists(wl)), math.Log2(1296); math.Abs(got-want) > 1e-9 {
+		t.Errorf("EntropyWithOptions() = %f, want %f", got, want)
+	}
+
+	// Rolls must all have the same number of dice
+	if _, err := LoadWordlist("mixed", strings.NewReader("1111 alpha\n11111 beta\n")); err == nil {
+		t.Error("LoadWordlist() with mixed dice counts should return an error")
+	}
+}
+
+// TestWordlistSizes runs registered lists of 1,296 (four dice) and 2,048
+// entries (five dice, most rolls missing) through the size, entropy,
+// validation and generation functions, none of which may assume 7,776.
+func TestWordlistSizes(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		size int
+		dice int
+	}{
+		{"Sizes Short", 1296, 4},
+		{"Sizes Sparse", 2048, 5},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var data strings.Builder
+			for i := 0; i < tt.size; i++ {
+				// Spread the sparse list's words over the rolls
+				roll := i * rollCount(tt.dice) / tt.size
+				fmt.Fprintf(&data, "%s\tword%d\n", indexToRoll(roll, tt.dice), i)
+			}
+			lang, err := RegisterLanguage(tt.name, strings.NewReader(data.String()))
+			if err != nil {
+				t.Fatalf("RegisterLanguage() error = %v", err)
+			}
+
+			bits := math.Log2(float64(tt.size))
+			if got := WordlistSizeByLanguage(lang); got != tt.size {
+				t.Errorf("WordlistSizeByLanguage() = %d, want %d", got, tt.size)
+			}
+			if got := EntropyForLanguage(6, lang); math.Abs(got-6*bits) > 1e-9 {
+				t.Errorf("EntropyForLanguage(6) = %f, want %f", got, 6*bits)
+			}
+			if got := EntropyWithOptions(6, WithLanguage(lang)); math.Abs(got-6*bits) > 1e-9 {
+				t.Errorf("EntropyWithOptions(6) = %f, want %f", got, 6*bits)
+			}
+			info, err := WordlistInfoByLanguage(lang)
+			if err != nil || info.Size != tt.size || info.DiceCount != tt.dice || math.Abs(info.BitsPerWord-bits) > 1e-9 {
+				t.Errorf("WordlistInfoByLanguage() = %+v, %v, want %d words of %d dice, %f bits", info, err, tt.size, tt.dice, bits)
+			}
+			want := new(big.Int).Exp(big.NewInt(int64(tt.size)), big.NewInt(6), nil)
+			if got := AttackKeyspace(6, lang); got.Cmp(want) != 0 {
+				t.Errorf("AttackKeyspace(6) = %v, want %v", got, want)
+			}
+			if err := ValidateWordlist(lang); err != nil {
+				t.Errorf("ValidateWordlist() error = %v", err)
+			}
+
+			words, err := GenerateWords(20, lang)
+			if err != nil {
+				t.Fatalf("GenerateWords() error = %v", err)
+			}
+			for _, word := range words {
+				if !strings.HasPrefix(word, "Word") {
+					t.Errorf("word %q isn't from the list", word)
+				}
+			}
+		})
+	}
+}
+
+func TestBIP39English(t *testing.T) {
+	if err := ValidateWordlist(LanguageBIP39English); err != nil {
+		t.Fatalf("ValidateWordlist() error = %v", err)
+	}
+	info, err := WordlistInfoByLanguage(LanguageBIP39English)
+	if err != nil || info.Size != 2048 || info.BitsPerWord != 11 || info.DiceCount != 0 {
+		t.Fatalf("WordlistInfoByLanguage() = %+v, %v, want 2048 words, 11 bits, no dice", info, err)
+	}
+	if got := EntropyForLanguage(12, LanguageBIP39English); got != 132 {
+		t.Errorf("EntropyForLanguage(12) = %f, want 132", got)
+	}
+
+	for roll, want := range map[string]string{"0": "Abandon", "1024": "Length", "2047": "Zoo"} {
+		if got, err := WordAt(roll, LanguageBIP39English); err != nil || got != want {
+			t.Errorf("WordAt(%q) = %q, %v, want %q", roll, got, err, want)
+		}
+	}
+	for _, roll := range []string{"2048", "-1", "01", "11111", ""} {
+		if _, err := WordAt(roll, LanguageBIP39English); err == nil {
+			t.Errorf("WordAt(%q) should return an error", roll)
+		}
+	}
+
+	_, words, err := GenerateWithRolledWords(12, WithLanguage(LanguageBIP39English))
+	if err != nil {
+		t.Fatalf("GenerateWithRolledWords() error = %v", err)
+	}
+	for _, w := range words {
+		if got, err := WordAt(w.Roll, LanguageBIP39English); err != nil || got != w.Word {
+			t.Errorf("roll %q gave %q, but WordAt() = %q, %v", w.Roll, w.Word, got, err)
+		}
+	}
+
+	// 11 bits per word from two bytes, ignoring the top five bits
+	r := bytes.NewReader([]byte{0xff, 0xff, 0x04, 0x00})
+	passphrase, err := GenerateWithOptions(2, WithLanguage(LanguageBIP39English), WithRandReader(r))
+	if err != nil || passphrase != "ZooLength" {
+		t.Errorf("GenerateWithOptions() = %q, %v, want %q", passphrase, err, "ZooLength")
+	}
+
+	if err := PrintWordlist(io.Discard, LanguageBIP39English); err == nil {
+		t.Error("PrintWordlist() should refuse a list without dice rolls")
+	}
+}
+
+func TestWeightedWordlist(t *testing.T) {
+	weights := map[string]float64{"common": 6, "usual": 3, "rare": 1}
+	wl, err := NewWeightedWordlist("weighted", weights)
+	if err != nil {
+		t.Fatalf("NewWeightedWordlist() error = %v", err)
+	}
+	if wl.Size() != 3 || wl.DiceCount() != 1 {
+		t.Fatalf("Size() = %d, DiceCount() = %d, want 3 words and 1 die", wl.Size(), wl.DiceCount())
+	}
+
+	// Shannon entropy of the 0.6/0.3/0.1 distribution
+	want := -(0.6*math.Log2(0.6) + 0.3*math.Log2(0.3) + 0.1*math.Log2(0.1))
+	if got := wl.Info().BitsPerWord; math.Abs(got-want) > 1e-9 {
+		t.Errorf("Info().BitsPerWord = %f, want %f", got, want)
+	}
+	if got := EntropyWithOptions(5, WithWordlists(wl)); math.Abs(got-5*want) > 1e-9 {
+		t.Errorf("EntropyWithOptions(5) = %f, want %f", got, 5*want)
+	}
+
+	const draws = 6000
+	counts := map[string]int{}
+	for i := 0; i < draws/6; i++ {
+		p, err := GenerateWithOptions(6, WithWordlists(wl), WithSeparator(" "), WithCapitalizer(strings.ToLower))
+		if err != nil {
+			t.Fatalf("GenerateWithOptions() error = %v", err)
+		}
+		for _, w := range strings.Fields(p) {
+			counts[w]++
+		}
+	}
+	for word, weight := range weights {
+		// Within about 6 standard deviations of the expected count
+		expected := draws * weight / 10
+		if got := float64(counts[word]); math.Abs(got-expected) > 6*math.Sqrt(expected) {
+			t.Errorf("%q drawn %d times out of %d, want about %.0f", word, counts[word], draws, expected)
+		}
+	}
+
+	for _, bad := range []map[string]float64{
+		nil,
+		{"word": 0},
+		{"word": -1},
+		{"word": math.Inf(1)},
+		{"word": math.NaN()},
+		{"": 1},
+	} {
+		if _, err := NewWeightedWordlist("bad", bad); err == nil {
+			t.Errorf("NewWeightedWordlist(%v) should return an error", bad)
+		}
+	}
+}
+
+func TestWords(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageReinhold} {
+		words := Words(lang)
+		if len(words) != WordlistSizeByLanguage(lang) {
+			t.Errorf("Words(%v) returned %d words, want %d", lang, len(words), WordlistSizeByLanguage(lang))
+		}
+		if !sort.StringsAreSorted(words) {
+			t.Errorf("Words(%v) is not sorted", lang)
+		}
+	}
+
+	mixed := Words(LanguageMixed)
+	if !sort.StringsAreSorted(mixed) || int64(len(mixed)) != AttackKeyspace(1, LanguageMixed).Int64() {
+		t.Errorf("Words(Mixed) returned %d words, want the distinct words of both lists", len(mixed))
+	}
+
+	// The result is a copy
+	words := Words(LanguageEnglish)
+	words[0] = "changed"
+	if Words(LanguageEnglish)[0] == "changed" {
+		t.Error("modifying the result of Words() changed the wordlist")
+	}
+
+	if Words(Language(99)) != nil {
+		t.Error("Words() with an unsupported language should return nil")
+	}
+}
+
+func TestWordlistInfoByLanguage(t *testing.T) {
+	for _, lang := range []Language{LanguageEnglish, LanguageRomanian, LanguageMixed} {
+		info, err := WordlistInfoByLanguage(lang)
+		if err != nil {
+			t.Fatalf("WordlistInfoByLanguage(%v) error = %v", lang, err)
+		}
+		if info.Size != WordlistSizeByLanguage(lang) {
+			t.Errorf("%v: Size = %d, want %d", lang, info.Size, WordlistSizeByLanguage(lang))
+		}
+		if math.Abs(info.BitsPerWord-EntropyForLanguage(1, lang)) > 1e-9 {
+			t.Errorf("%v: BitsPerWord = %f, want %f", lang, info.BitsPerWord, EntropyForLanguage(1, lang))
+		}
+		if info.DiceCount != 5 {
+			t.Errorf("%v: DiceCount = %d, want 5", lang, info.DiceCount)
+		}
+		if info.Name == "" {
+			t.Errorf("%v: Name is empty", lang)
+		}
+	}
+
+	info, _ := WordlistInfoByLanguage(LanguageEnglish)
+	if info.Name != "English" || info.Size != 7776 || math.Abs(info.BitsPerWord-12.925) > 0.001 {
+		t.Errorf("English info = %+v, want English, 7776 words, ~12.925 bits", info)
+	}
+
+	if _, err := WordlistInfoByLanguage(Language(99)); err == nil {
+		t.Error("WordlistInfoByLanguage() with an unsupported language should return an error")
+	}
+}
+
+// TestEnglishWordlistASCII guards the guarantee downstream systems with
+// strict charsets rely on: every English word is pure ASCII.
+func TestEnglishWordlistASCII(t *testing.T) {
+	words := builtin(LanguageEnglish).words
+	if len(words) != rollCombinations {
+		t.Fatalf("English wordlist has %d entries, want %d", len(words), rollCombinations)
+	}
+	for i, word := range words {
+		for _, r := range word {
+			if r > unicode.MaxASCII {
+				t.Errorf("English word %q for dice roll %s is not ASCII", word, indexToRoll(i, diceCount))
+				break
+			}
+		}
+	}
+
+	for _, tt := range []struct {
+		lang Language
+		want bool
+	}{
+		{LanguageEnglish, true},
+		{LanguageReinhold, true},
+		{LanguageBIP39English, true},
+	} {
+		if got := builtin(tt.lang).IsASCII(); got != tt.want {
+			t.Errorf("IsASCII() for %v = %v, want %v", tt.lang, got, tt.want)
+		}
+	}
+
+	// One non-ASCII word is enough
+	custom, err := NewWordlist("custom", map[string]string{"1": "abc", "2": "ăbc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if custom.IsASCII() {
+		t.Error("IsASCII() = true for a list with a non-ASCII word")
+	}
+}
+
+func TestHasUniquePrefixes(t *testing.T) {
+	wl, err := NewWordlist("prefixes", map[string]string{
+		"11111": "apple", "11112": "apricot", "11113": "banana", "11114": "ap",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		n    int
+		want bool
+	}{
+		{1, false}, // a...
+		{2, false}, // "ap" and "apple"
+		{3, true},  // ap, app, apr, ban
+		{10, true},
+	}
+	for _, tt := range tests {
+		if got := wl.HasUniquePrefixes(tt.n); got != tt.want {
+			t.Errorf("HasUniquePrefixes(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+
+	if err := wl.CheckUniquePrefixes(2); err == nil || !strings.Contains(err.Error(), `"ap"`) {
+		t.Errorf("CheckUniquePrefixes(2) error = %v, want one naming the prefix \"ap\"", err)
+	}
+	if err := wl.CheckUniquePrefixes(0); err == nil {
+		t.Error("CheckUniquePrefixes(0) should return an error")
+	}
+
+	// Case doesn't make prefixes distinct
+	mixedCase, _ := NewWordlist("case", map[string]string{"11111": "Apple", "11112": "apple"})
+	if mixedCase.HasUniquePrefixes(5) {
+		t.Error("HasUniquePrefixes() should ignore case")
+	}
+
+	// The EFF large list has unique words but not unique 3-letter prefixes
+	en, _ := WordlistByLanguage(LanguageEnglish)
+	if en.HasUniquePrefixes(3) {
+		t.Error("EFF large wordlist unexpectedly has unique 3-character prefixes")
+	}
+	if !en.HasUniquePrefixes(20) {
+		t.Error("EFF large wordlist words should be unique")
+	}
+}
+
+func TestDiffWordlists(t *testing.T) {
+	a, err := NewWordlist("a", map[string]string{"1": "alpha", "2": "beta", "3": "gamma", "4": "delta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := NewWordlist("b", map[string]string{"1": "alpha", "2": "Beta", "3": "delta", "4": "epsilon", "5": "zeta"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	onlyA, onlyB := DiffWordlists(a, b)
+	if want := []string{"beta", "gamma"}; !slices.Equal(onlyA, want) {
+		t.Errorf("DiffWordlists() onlyA = %q, want %q", onlyA, want)
+	}
+	if want := []string{"Beta", "epsilon", "zeta"}; !slices.Equal(onlyB, want) {
+		t.Errorf("DiffWordlists() onlyB = %q, want %q", onlyB, want)
+	}
+
+	english := builtin(LanguageEnglish)
+	if onlyA, onlyB := DiffWordlists(english, english); onlyA != nil || onlyB != nil {
+		t.Errorf("DiffWordlists() of a list with itself = %q, %q, want none", onlyA, onlyB)
+	}
+}